@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"time"
 )
 
 // Files properties to work with files
@@ -18,9 +19,56 @@ type Mongo struct {
 	Database string `mapstructure:"database"`
 }
 
+// CurrencyConfig selects where exchange rates come from. The static provider uses the coefficients
+// and the USD values of one unit of the other currencies in Rates,
+// the file and http providers read ECB-style XML or CSV rates, the format follows the file extension when it is empty.
+// Rates are cached for RatesTTL.
 type CurrencyConfig struct {
-	CoefficientEURtoUSD float64 `mapstructure:"coefficientEURtoUSD"`
-	CoefficientRUStoUSD float64 `mapstructure:"coefficientRUStoUSD"`
+	CoefficientEURtoUSD float64            `mapstructure:"coefficientEURtoUSD"`
+	CoefficientRUStoUSD float64            `mapstructure:"coefficientRUStoUSD"`
+	Rates               map[string]float64 `mapstructure:"rates"`
+	RateProvider        string             `mapstructure:"rateProvider"`
+	RatesFile           string             `mapstructure:"ratesFile"`
+	RatesURL            string             `mapstructure:"ratesURL"`
+	RatesFormat         string             `mapstructure:"ratesFormat"`
+	RatesTTL            time.Duration      `mapstructure:"ratesTTL"`
+	RatesTimeout        time.Duration      `mapstructure:"ratesTimeout"`
+}
+
+// SurveyEdition maps every salary field to the header names used by one edition of the survey
+type SurveyEdition struct {
+	Name    string              `mapstructure:"name"`
+	Columns map[string][]string `mapstructure:"columns"`
+}
+
+type SurveyConfig struct {
+	Editions []SurveyEdition `mapstructure:"editions"`
+}
+
+// SeniorityLevelConfig is a canonical level of seniority with the other ways it is written in survey answers
+type SeniorityLevelConfig struct {
+	Name    string   `mapstructure:"name"`
+	Aliases []string `mapstructure:"aliases"`
+}
+
+// TaxonomyConfig holds the controlled vocabularies of salary fields, the levels of seniority are listed
+// from the least senior one
+type TaxonomyConfig struct {
+	Seniority []SeniorityLevelConfig `mapstructure:"seniority"`
+}
+
+type ImportConfig struct {
+	BatchSize    int           `mapstructure:"batchSize"`
+	TempDir      string        `mapstructure:"tempDir"`
+	JobRetention time.Duration `mapstructure:"jobRetention"`
+}
+
+// StatsConfig sets the smallest group of salaries whose statistics can be shown and the histogram limits.
+// Salaries further than OutlierFactor interquartile ranges from the quartiles are histogram outliers.
+type StatsConfig struct {
+	MinGroupSize  int     `mapstructure:"minGroupSize"`
+	OutlierFactor float64 `mapstructure:"outlierFactor"`
+	MaxBuckets    int     `mapstructure:"maxBuckets"`
 }
 
 type Config struct {
@@ -28,6 +76,10 @@ type Config struct {
 	LoggerConfig   `mapstructure:"logger"`
 	Mongo          `mapstructure:"mongo"`
 	CurrencyConfig `mapstructure:"currency"`
+	SurveyConfig   `mapstructure:"survey"`
+	ImportConfig   `mapstructure:"import"`
+	StatsConfig    `mapstructure:"stats"`
+	TaxonomyConfig `mapstructure:"taxonomy"`
 }
 
 func LoadConfig() (config Config, logger *logrus.Logger, err error) {
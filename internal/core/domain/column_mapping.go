@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ColumnSalary           = "salary"
+	ColumnLevelOfSeniority = "levelofseniority"
+	ColumnYearsTotal       = "yearstotal"
+	ColumnCountry          = "country"
+	ColumnLevelOfEnglish   = "levelofenglish"
+	ColumnResponseId       = "responseid"
+)
+
+// ColumnMapping keeps the position of every salary field in the rows of an uploaded file
+type ColumnMapping struct {
+	Edition string
+	Indexes map[string]int
+}
+
+// Value returns the trimmed value of the field in the line and false when the line is too short to contain it
+func (cm ColumnMapping) Value(line []string, column string) (string, bool) {
+	index, ok := cm.Indexes[column]
+	if !ok || index >= len(line) {
+		return "", false
+	}
+	return strings.TrimSpace(line[index]), true
+}
+
+type MissingColumnsError struct {
+	Edition string
+	Columns []string
+}
+
+func (e *MissingColumnsError) Error() string {
+	return fmt.Sprintf("Required columns are missing in the file header for survey edition %q: %s",
+		e.Edition, strings.Join(e.Columns, ", "))
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// isoCurrencies are the active ISO 4217 currency codes, fund and precious metal codes are left out
+var isoCurrencies = codeSet(`
+	AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB BRL BSD BTN BWP BYN BZD
+	CAD CDF CHF CLP CNY COP CRC CUC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP
+	GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD KYD
+	KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN NAD NGN NIO NOK
+	NPR NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP SLE SLL SOS
+	SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX USD UYU UZS VED VES VND VUV WST
+	XAF XCD XCG XOF XPF YER ZAR ZMW ZWG ZWL`)
+
+// currencyAliases are the symbols, withdrawn codes and local names of currencies used in survey answers
+var currencyAliases = map[string]string{
+	"RUS": "RUB",
+	"RUR": "RUB",
+	"РУБ": "RUB",
+	"₽":   "RUB",
+	"$":   "USD",
+	"US$": "USD",
+	"€":   "EUR",
+	"£":   "GBP",
+	"₴":   "UAH",
+	"ГРН": "UAH",
+	"₸":   "KZT",
+	"ZŁ":  "PLN",
+	"BYR": "BYN",
+	"₾":   "GEL",
+	"₹":   "INR",
+	"₺":   "TRY",
+}
+
+// UnknownCurrencyError is returned for a currency that is neither an ISO 4217 code nor a known alias
+type UnknownCurrencyError struct {
+	Currency string
+}
+
+func (e *UnknownCurrencyError) Error() string {
+	return fmt.Sprintf("The currency %s is not an ISO 4217 code", e.Currency)
+}
+
+// NormalizeCurrency returns the ISO 4217 code of a code, symbol or alias in any case
+func NormalizeCurrency(currency string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(currency))
+	if alias, ok := currencyAliases[code]; ok {
+		return alias, nil
+	}
+	if isoCurrencies[code] {
+		return code, nil
+	}
+	return "", &UnknownCurrencyError{Currency: strings.TrimSpace(currency)}
+}
+
+func codeSet(codes string) map[string]bool {
+	set := map[string]bool{}
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}
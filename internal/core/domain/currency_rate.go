@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+const (
+	CurrencyRateCreated = "created"
+	CurrencyRateUpdated = "updated"
+	CurrencyRateDeleted = "deleted"
+)
+
+var (
+	ErrCurrencyRateNotFound = errors.New("Currency rate is not found")
+	ErrCurrencyRateExists   = errors.New("Currency rate already exists")
+)
+
+// CurrencyRate is the USD value of one unit of the currency set by an admin, it replaces the rate of the rate provider
+type CurrencyRate struct {
+	Currency  string    `json:"currency" bson:"_id"`
+	USDRate   float64   `json:"usdRate" bson:"usdRate"`
+	UpdatedBy string    `json:"updatedBy" bson:"updatedBy"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// CurrencyRateChange records who created, updated or deleted a currency rate and when.
+// PreviousRate is not set for a created rate, USDRate is not set for a deleted one.
+type CurrencyRateChange struct {
+	Id           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Currency     string             `json:"currency" bson:"currency"`
+	Action       string             `json:"action" bson:"action"`
+	PreviousRate *float64           `json:"previousRate,omitempty" bson:"previousRate,omitempty"`
+	USDRate      *float64           `json:"usdRate,omitempty" bson:"usdRate,omitempty"`
+	ChangedBy    string             `json:"changedBy" bson:"changedBy"`
+	ChangedAt    time.Time          `json:"changedAt" bson:"changedAt"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "fmt"
+
+// ValidationError is returned when a request can not be processed because of its content
+type ValidationError struct {
+	Message string
+}
+
+func NewValidationError(format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrExchangeRatesNotFound = errors.New("Exchange rates are not found")
+
+// ExchangeRates are the USD values of one unit of every currency on Date
+type ExchangeRates struct {
+	Date  time.Time          `json:"date" bson:"_id"`
+	Rates map[string]float64 `json:"rates" bson:"rates"`
+}
+
+// USDRate returns how many USD one unit of the currency is worth, ok is false when the currency has no rate
+func (er *ExchangeRates) USDRate(currency string) (float64, bool) {
+	if currency == "USD" {
+		return 1, true
+	}
+	rate, ok := er.Rates[currency]
+	return rate, ok && rate > 0
+}
+
+// Rate returns how many units of to one unit of from is worth, ok is false when either currency has no rate
+func (er *ExchangeRates) Rate(from string, to string) (float64, bool) {
+	if from == to {
+		return 1, true
+	}
+	fromUSD, ok := er.USDRate(from)
+	if !ok {
+		return 0, false
+	}
+	toUSD, ok := er.USDRate(to)
+	if !ok {
+		return 0, false
+	}
+	return fromUSD / toUSD, true
+}
+
+// Convert returns the amount of from in the currency to
+func (er *ExchangeRates) Convert(amount float64, from string, to string) (float64, bool) {
+	rate, ok := er.Rate(from, to)
+	if !ok {
+		return 0, false
+	}
+	return amount * rate, true
+}
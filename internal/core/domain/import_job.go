@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	"time"
+)
+
+const (
+	ImportJobStatusQueued    = "queued"
+	ImportJobStatusRunning   = "running"
+	ImportJobStatusSucceeded = "succeeded"
+	ImportJobStatusFailed    = "failed"
+	ImportJobStatusCancelled = "cancelled"
+)
+
+var (
+	ErrImportJobNotFound = errors.New("Import job is not found")
+	ErrImportJobFinished = errors.New("Import job is already finished")
+)
+
+// ImportJob tracks a salary upload processed in the background. Result holds the upload report once the job succeeded.
+type ImportJob struct {
+	Id            string                       `json:"id"`
+	Status        string                       `json:"status"`
+	FileName      string                       `json:"fileName,omitempty"`
+	Uploader      string                       `json:"uploader,omitempty"`
+	Mode          string                       `json:"mode,omitempty"`
+	RowsProcessed int                          `json:"rowsProcessed"`
+	RowsSkipped   int                          `json:"rowsSkipped"`
+	Errors        []*response.RowError         `json:"errors"`
+	Error         string                       `json:"error,omitempty"`
+	Result        *response.SalaryUploadReport `json:"result,omitempty"`
+	CreatedAt     time.Time                    `json:"createdAt"`
+	StartedAt     *time.Time                   `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time                   `json:"finishedAt,omitempty"`
+}
+
+func (j ImportJob) IsFinished() bool {
+	return j.Status == ImportJobStatusSucceeded || j.Status == ImportJobStatusFailed || j.Status == ImportJobStatusCancelled
+}
@@ -1,8 +1,42 @@
 package request
 
+const (
+	RateDateCollection = "collection"
+	RateDateToday      = "today"
+)
+
 type ConditionForFilteringSalaries struct {
-	Salary           string `json:"salary"`
-	LevelOfSeniority string `json:"levelOfSeniority"`
-	YearsTotal       string `json:"yearsTotal"`
-	Country          string `json:"country"`
+	Salary    string   `json:"salary"`
+	SalaryMin *float64 `json:"salaryMin,omitempty"`
+	SalaryMax *float64 `json:"salaryMax,omitempty"`
+	// SalaryCurrency is the currency of SalaryMin and SalaryMax, USD when it is empty.
+	// The bounds are converted at the same rates as the USD amounts they are compared with, see RateDate.
+	SalaryCurrency    string        `json:"salaryCurrency,omitempty"`
+	LevelOfSeniority  string        `json:"levelOfSeniority"`
+	LevelsOfSeniority *ValuesFilter `json:"levelsOfSeniority,omitempty"`
+	YearsTotal        string        `json:"yearsTotal"`
+	YearsTotalMin     *float64      `json:"yearsTotalMin,omitempty"`
+	YearsTotalMax     *float64      `json:"yearsTotalMax,omitempty"`
+	Country           string        `json:"country"`
+	Countries         *ValuesFilter `json:"countries,omitempty"`
+	LevelOfEnglish    string        `json:"levelOfEnglish,omitempty"`
+	LevelsOfEnglish   *ValuesFilter `json:"levelsOfEnglish,omitempty"`
+	Version           string        `json:"version,omitempty"`
+	// SeniorityMin and SeniorityMax are levels of the seniority taxonomy bounding the rank of the level of seniority
+	SeniorityMin string `json:"seniorityMin,omitempty"`
+	SeniorityMax string `json:"seniorityMax,omitempty"`
+	// SeniorityRankMin and SeniorityRankMax are the ranks of SeniorityMin and SeniorityMax, set by the salary service
+	SeniorityRankMin *float64 `json:"-"`
+	SeniorityRankMax *float64 `json:"-"`
+	// RateDate selects the exchange rates of the USD amounts: the rates of the collection date of every dataset,
+	// stored at import, or today's rates
+	RateDate string `json:"rateDate,omitempty"`
+	// USDRates are today's USD values of one unit of every currency, set by the salary service for RateDateToday
+	USDRates map[string]float64 `json:"-"`
+}
+
+// ValuesFilter selects salaries whose value is one of In and none of NotIn, values are compared case-insensitively
+type ValuesFilter struct {
+	In    []string `json:"in,omitempty"`
+	NotIn []string `json:"notIn,omitempty"`
 }
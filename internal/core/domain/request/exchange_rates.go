@@ -0,0 +1,21 @@
+package request
+
+import "io"
+
+const (
+	RatesFormatXML = "xml"
+	RatesFormatCSV = "csv"
+)
+
+// ExchangeRatesImport is an ECB-style rates file, the format follows the file extension when it is empty
+type ExchangeRatesImport struct {
+	File     io.Reader
+	FileName string
+	Format   string
+}
+
+// CurrencyRateRequest sets the USD value of one unit of the currency, the currency of an update is taken from the path
+type CurrencyRateRequest struct {
+	Currency string  `json:"currency,omitempty"`
+	USDRate  float64 `json:"usdRate"`
+}
@@ -0,0 +1,7 @@
+package request
+
+// SalaryExportRequest selects the salaries to export, TargetCurrency is the currency of the converted amounts, USD when it is empty
+type SalaryExportRequest struct {
+	ConditionForFilteringSalaries
+	TargetCurrency string `json:"targetCurrency,omitempty"`
+}
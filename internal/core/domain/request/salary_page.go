@@ -0,0 +1,25 @@
+package request
+
+const (
+	SortBySalary     = "salary"
+	SortByYearsTotal = "yearsTotal"
+	SortByCountry    = "country"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// SortByFields are the fields the filtered salaries can be sorted by, salaries are sorted by their USD amount
+var SortByFields = []string{SortBySalary, SortByYearsTotal, SortByCountry}
+
+// SalaryPageRequest selects Limit salaries matching the filter after skipping Offset of them.
+// Salaries are sorted by SortBy in SortOrder, in the order they were stored when SortBy is empty.
+// TargetCurrency is the currency the salaries are converted to, USD when it is empty.
+type SalaryPageRequest struct {
+	ConditionForFilteringSalaries
+	Limit          int    `json:"limit,omitempty"`
+	Offset         int    `json:"offset,omitempty"`
+	SortBy         string `json:"sortBy,omitempty"`
+	SortOrder      string `json:"sortOrder,omitempty"`
+	TargetCurrency string `json:"targetCurrency,omitempty"`
+}
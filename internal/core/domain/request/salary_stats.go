@@ -0,0 +1,25 @@
+package request
+
+// SalaryStatsRequest selects salaries like the filter, TargetCurrency is the currency of the statistics,
+// USD when it is empty
+type SalaryStatsRequest struct {
+	ConditionForFilteringSalaries
+	TargetCurrency string `json:"targetCurrency,omitempty"`
+}
+
+// SalaryHistogramRequest selects the salaries of the histogram. BucketWidth fixes the width of the buckets and
+// Buckets fixes their number, the width is chosen from the spread of the salaries when both are empty.
+// OutlierMin and OutlierMax replace the configured outlier bounds. All amounts are in the target currency.
+type SalaryHistogramRequest struct {
+	SalaryStatsRequest
+	BucketWidth *float64 `json:"bucketWidth,omitempty"`
+	Buckets     int      `json:"buckets,omitempty"`
+	OutlierMin  *float64 `json:"outlierMin,omitempty"`
+	OutlierMax  *float64 `json:"outlierMax,omitempty"`
+}
+
+// SalaryGroupsRequest computes the statistics for every group of the one or two GroupBy dimensions
+type SalaryGroupsRequest struct {
+	SalaryStatsRequest
+	GroupBy []string `json:"groupBy"`
+}
@@ -0,0 +1,30 @@
+package request
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	"io"
+	"time"
+)
+
+const (
+	UploadModeReplace = "replace"
+	UploadModeAppend  = "append"
+	UploadModeUpsert  = "upsert"
+)
+
+type SalaryUpload struct {
+	File     io.Reader
+	FileName string
+	Uploader string
+	Mode     string
+	// CollectedAt is the date the survey was collected, the amounts are converted at the exchange rates of that date.
+	// Without it the current rates are used.
+	CollectedAt time.Time
+	// RatesDate and Rates are set by the salary service to the exchange rates used for the import
+	RatesDate time.Time
+	Rates     map[string]float64
+	// DryRun parses and validates the file without writing anything to the repository
+	DryRun bool
+	// Progress is called with the report of the rows processed so far after every stored batch
+	Progress func(report *response.SalaryUploadReport)
+}
@@ -1,8 +1,41 @@
 package response
 
+// SalariesPage describes one page of the filtered salaries, Total counts the matching salaries of every page
+type SalariesPage struct {
+	Total     int64  `json:"total"`
+	Limit     int    `json:"limit"`
+	Offset    int    `json:"offset"`
+	SortBy    string `json:"sortBy,omitempty"`
+	SortOrder string `json:"sortOrder,omitempty"`
+}
+
+// SalariesResponse is a filtered salary. Salary is Amount rounded to whole units, Amount is OriginalAmount
+// in OriginalCurrency converted to Currency, Rate is the number of Currency units for one OriginalCurrency unit.
+// A salary that can not be converted has no Salary, Amount and Rate, ConversionError tells why.
 type SalariesResponse struct {
-	Salary           string `json:"salary"`
-	LevelOfSeniority string `json:"levelOfSeniority"`
-	YearsTotal       string `json:"yearsTotal"`
-	Country          string `json:"country"`
+	Salary           string   `json:"salary"`
+	LevelOfSeniority string   `json:"levelOfSeniority"`
+	YearsTotal       string   `json:"yearsTotal"`
+	Country          string   `json:"country"`
+	OriginalAmount   float64  `json:"originalAmount"`
+	OriginalCurrency string   `json:"originalCurrency"`
+	Amount           *float64 `json:"amount"`
+	Currency         string   `json:"currency"`
+	Rate             *float64 `json:"rate"`
+	ConversionError  string   `json:"conversionError,omitempty"`
+}
+
+// SalaryExportRow is an exported salary with its amount as answered and converted to Currency,
+// a salary that can not be converted has no Amount and ConversionError tells why
+type SalaryExportRow struct {
+	ResponseId       string   `json:"responseId"`
+	LevelOfSeniority string   `json:"levelOfSeniority"`
+	YearsTotal       float64  `json:"yearsTotal"`
+	Country          string   `json:"country"`
+	LevelOfEnglish   string   `json:"levelOfEnglish"`
+	OriginalAmount   float64  `json:"originalAmount"`
+	OriginalCurrency string   `json:"originalCurrency"`
+	Amount           *float64 `json:"amount"`
+	Currency         string   `json:"currency"`
+	ConversionError  string   `json:"conversionError,omitempty"`
 }
@@ -0,0 +1,9 @@
+package response
+
+// ExchangeRatesImportReport describes the days stored by an exchange rates import, dates are written like 2021-06-30
+type ExchangeRatesImportReport struct {
+	Days       int      `json:"days"`
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Currencies []string `json:"currencies"`
+}
@@ -1,7 +1,44 @@
 package response
 
 type SalaryUploadReport struct {
+	Version        string `json:",omitempty"`
 	TotalRecords   int
 	SkippedRecords int
-	Errors         []string
+	Errors         []*RowError
+	Samples        []*SalarySample `json:",omitempty"`
+	// UnmappedSeniority lists the levels of seniority of the file that are not in the seniority taxonomy
+	UnmappedSeniority []string `json:",omitempty"`
+}
+
+// SalarySample is a parsed row returned by a dry-run upload
+type SalarySample struct {
+	Amount           float64
+	AmountMin        float64
+	AmountMax        float64
+	Currency         string
+	AmountUSD        float64
+	LevelOfSeniority string
+	SeniorityRank    int
+	YearsTotal       float64
+	Country          string
+	LevelOfEnglish   string
+	ResponseId       string `json:",omitempty"`
+}
+
+const (
+	RowErrorInvalidRow        = "invalid_row"
+	RowErrorMissingValue      = "missing_value"
+	RowErrorInvalidSalary     = "invalid_salary"
+	RowErrorUnknownCurrency   = "unknown_currency"
+	RowErrorInvalidYears      = "invalid_years"
+	RowErrorMissingResponseId = "missing_response_id"
+)
+
+// RowError describes why a row of an uploaded file was skipped. Line is the line number in the file.
+type RowError struct {
+	Line    int
+	Column  string `json:",omitempty"`
+	Value   string `json:",omitempty"`
+	Code    string
+	Message string
 }
@@ -0,0 +1,57 @@
+package response
+
+// SalaryStatsResponse has the statistics computed in BaseCurrency and converted to Currency,
+// Rate is the number of Currency units for one BaseCurrency unit. Median and the percentiles are approximate
+// and are one of the salaries, the median of two salaries is the lower one rather than their average.
+type SalaryStatsResponse struct {
+	BaseCurrency string  `json:"baseCurrency"`
+	Currency     string  `json:"currency"`
+	Rate         float64 `json:"rate"`
+	Count        int     `json:"count"`
+	Min          float64 `json:"min"`
+	Max          float64 `json:"max"`
+	Mean         float64 `json:"mean"`
+	Median       float64 `json:"median"`
+	P10          float64 `json:"p10"`
+	P25          float64 `json:"p25"`
+	P75          float64 `json:"p75"`
+	P90          float64 `json:"p90"`
+}
+
+type SalaryGroupsResponse struct {
+	GroupBy      []string `json:"groupBy"`
+	MinGroupSize int      `json:"minGroupSize"`
+	// SuppressedGroups is the number of groups left out because they have fewer salaries than MinGroupSize
+	SuppressedGroups int                    `json:"suppressedGroups"`
+	Groups           []*SalaryGroupResponse `json:"groups"`
+}
+
+type SalaryGroupResponse struct {
+	Group map[string]interface{} `json:"group"`
+	SalaryStatsResponse
+}
+
+type SalaryHistogramResponse struct {
+	BaseCurrency string                     `json:"baseCurrency"`
+	Currency     string                     `json:"currency"`
+	Rate         float64                    `json:"rate"`
+	Count        int                        `json:"count"`
+	BucketWidth  float64                    `json:"bucketWidth"`
+	Buckets      []*HistogramBucketResponse `json:"buckets"`
+	Outliers     HistogramOutliersResponse  `json:"outliers"`
+}
+
+// HistogramBucketResponse counts the salaries from From up to To, the last bucket includes To
+type HistogramBucketResponse struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+// HistogramOutliersResponse counts the salaries below LowerBound and above UpperBound left out of the buckets
+type HistogramOutliersResponse struct {
+	LowerBound float64 `json:"lowerBound"`
+	UpperBound float64 `json:"upperBound"`
+	Below      int     `json:"below"`
+	Above      int     `json:"above"`
+}
@@ -1,10 +1,31 @@
 package domain
 
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
 type Salary struct {
-	Salary           string
+	DatasetId  primitive.ObjectID `bson:"datasetid,omitempty"`
+	FileName   string             `bson:"filename,omitempty"`
+	Uploader   string             `bson:"uploader,omitempty"`
+	UploadedAt time.Time          `bson:"uploadedat,omitempty"`
+	ResponseId string             `bson:"responseid,omitempty"`
+	// Amount is the salary in the ISO 4217 Currency, AmountUSD is the same salary converted to USD at import.
+	// A salary range is kept in AmountMin and AmountMax with its middle in Amount.
+	Amount           float64
+	AmountMin        float64
+	AmountMax        float64
 	Currency         string
+	AmountUSD        float64
 	LevelOfSeniority string
-	YearsTotal       string
+	SeniorityRank    int
+	YearsTotal       float64
 	Country          string
 	LevelOfEnglish   string
+	// RawSalary, RawYearsTotal and RawLevelOfSeniority keep the values exactly as they were in the uploaded file,
+	// LevelOfSeniority is the level of the seniority taxonomy with its SeniorityRank or the trimmed value with rank 0
+	RawSalary           string
+	RawYearsTotal       string
+	RawLevelOfSeniority string
 }
@@ -0,0 +1,26 @@
+package domain
+
+import "fmt"
+
+// SalaryConversion is the amount of a salary converted to Currency. It is a new value, the salary is never changed.
+// Rate is the number of Currency units for one OriginalCurrency unit.
+type SalaryConversion struct {
+	OriginalAmount   float64
+	OriginalCurrency string
+	Amount           float64
+	Currency         string
+	Rate             float64
+}
+
+// ConversionError is returned for a salary whose amount can not be converted to Currency
+type ConversionError struct {
+	ResponseId       string
+	OriginalAmount   float64
+	OriginalCurrency string
+	Currency         string
+	Reason           string
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("The salary %v %s can not be converted to %s: %s", e.OriginalAmount, e.OriginalCurrency, e.Currency, e.Reason)
+}
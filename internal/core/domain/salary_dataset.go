@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+const (
+	DatasetStatusStaging = "staging"
+	DatasetStatusReady   = "ready"
+)
+
+var (
+	ErrDatasetNotFound = errors.New("Salary dataset is not found")
+	ErrDatasetActive   = errors.New("Salary dataset is active and can not be deleted")
+)
+
+// SalaryDataset is one version of the salary data created by an upload. The ready dataset activated last is the live one.
+// CollectedAt is the date the survey was collected, RatesDate the date of the exchange rates its amounts were converted with
+// and Rates the USD values of one unit of every currency of those rates.
+type SalaryDataset struct {
+	Id          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Status      string             `json:"status" bson:"status"`
+	Mode        string             `json:"mode,omitempty" bson:"mode,omitempty"`
+	FileName    string             `json:"fileName,omitempty" bson:"fileName,omitempty"`
+	Uploader    string             `json:"uploader,omitempty" bson:"uploader,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	CollectedAt *time.Time         `json:"collectedAt,omitempty" bson:"collectedAt,omitempty"`
+	RatesDate   *time.Time         `json:"ratesDate,omitempty" bson:"ratesDate,omitempty"`
+	Rates       map[string]float64 `json:"rates,omitempty" bson:"rates,omitempty"`
+	ActivatedAt *time.Time         `json:"activatedAt,omitempty" bson:"activatedAt,omitempty"`
+	Active      bool               `json:"active" bson:"-"`
+}
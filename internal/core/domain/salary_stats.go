@@ -0,0 +1,48 @@
+package domain
+
+const (
+	GroupByLevelOfSeniority = "levelOfSeniority"
+	GroupByCountry          = "country"
+	GroupByYearsTotal       = "yearsTotal"
+	GroupByLevelOfEnglish   = "levelOfEnglish"
+)
+
+// GroupByDimensions are the salary fields statistics can be grouped by, years are grouped by whole years
+var GroupByDimensions = []string{GroupByLevelOfSeniority, GroupByCountry, GroupByYearsTotal, GroupByLevelOfEnglish}
+
+// SalaryStats describes the USD amounts of the selected salaries, Median and the percentiles are approximate
+// values taken from the amounts
+type SalaryStats struct {
+	Count  int
+	Min    float64
+	Max    float64
+	Mean   float64
+	Median float64
+	P10    float64
+	P25    float64
+	P75    float64
+	P90    float64
+}
+
+// HistogramLayout places the USD amounts from Lower to Upper into Buckets buckets of Width beginning at Start,
+// the last bucket includes its upper edge. Amounts below Lower or above Upper are outliers.
+type HistogramLayout struct {
+	Start   float64
+	Width   float64
+	Buckets int
+	Lower   float64
+	Upper   float64
+}
+
+// SalaryHistogram counts the salaries in every bucket of a HistogramLayout and the outliers on both sides
+type SalaryHistogram struct {
+	Counts []int
+	Below  int
+	Above  int
+}
+
+// SalaryGroupStats is the statistics of one group, Group holds the value of every dimension of the group
+type SalaryGroupStats struct {
+	Group       map[string]interface{} `bson:"_id"`
+	SalaryStats `bson:",inline"`
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SeniorityLevel is a canonical level of seniority, a level with a greater Rank is more senior
+type SeniorityLevel struct {
+	Name    string   `json:"name"`
+	Rank    int      `json:"rank"`
+	Aliases []string `json:"aliases"`
+}
+
+// SeniorityTaxonomy holds the canonical levels of seniority ordered by rank
+// and finds the level of any of their names and aliases
+type SeniorityTaxonomy struct {
+	Levels []*SeniorityLevel `json:"levels"`
+	index  map[string]*SeniorityLevel
+}
+
+// NewSeniorityTaxonomy ranks the levels from 1 in the given order, a name or an alias may belong to one level only
+func NewSeniorityTaxonomy(levels []*SeniorityLevel) (*SeniorityTaxonomy, error) {
+	taxonomy := &SeniorityTaxonomy{Levels: []*SeniorityLevel{}, index: map[string]*SeniorityLevel{}}
+	for i, level := range levels {
+		name := strings.TrimSpace(level.Name)
+		if len(name) == 0 {
+			return nil, fmt.Errorf("Seniority level %d has no name", i+1)
+		}
+
+		ranked := &SeniorityLevel{Name: name, Rank: i + 1, Aliases: []string{}}
+		for j, value := range append([]string{name}, level.Aliases...) {
+			key := seniorityKey(value)
+			if len(key) == 0 {
+				continue
+			}
+			if other, ok := taxonomy.index[key]; ok {
+				if other == ranked {
+					continue
+				}
+				return nil, fmt.Errorf("Seniority %q belongs to both %s and %s", strings.TrimSpace(value), other.Name, name)
+			}
+			taxonomy.index[key] = ranked
+			if j > 0 {
+				ranked.Aliases = append(ranked.Aliases, strings.TrimSpace(value))
+			}
+		}
+		taxonomy.Levels = append(taxonomy.Levels, ranked)
+	}
+	return taxonomy, nil
+}
+
+// Level returns the level whose name or alias is the value, the case, dots, dashes and extra spaces are ignored
+func (st *SeniorityTaxonomy) Level(value string) (*SeniorityLevel, bool) {
+	level, ok := st.index[seniorityKey(value)]
+	return level, ok
+}
+
+// Canonical returns the name of the level of the value, a value of no level is returned trimmed
+func (st *SeniorityTaxonomy) Canonical(value string) string {
+	if level, ok := st.Level(value); ok {
+		return level.Name
+	}
+	return strings.TrimSpace(value)
+}
+
+// Names returns the names of the levels ordered by rank
+func (st *SeniorityTaxonomy) Names() []string {
+	names := make([]string, 0, len(st.Levels))
+	for _, level := range st.Levels {
+		names = append(names, level.Name)
+	}
+	return names
+}
+
+// seniorityKey turns "Team-Lead", "team lead" and "Team lead." into the same key
+func seniorityKey(value string) string {
+	value = strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '-', '_', '/':
+			return ' '
+		}
+		return r
+	}, strings.ToLower(value))
+	return strings.Join(strings.Fields(value), " ")
+}
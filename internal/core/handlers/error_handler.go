@@ -1,7 +1,8 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
 	"github.com/inkoba/app_for_HR/internal/core/domain/response"
 	"github.com/sirupsen/logrus"
 
@@ -9,13 +10,32 @@ import (
 )
 
 func HandleError(w http.ResponseWriter, message string, logger *logrus.Logger) {
+	HandleErrorWithStatus(w, http.StatusInternalServerError, message, logger)
+}
+
+// HandleServiceError responds with the status code matching the kind of the service error
+func HandleServiceError(w http.ResponseWriter, err error, logger *logrus.Logger) {
+	status := http.StatusInternalServerError
+
+	var missingColumns *domain.MissingColumnsError
+	var validationError *domain.ValidationError
+	var unknownCurrency *domain.UnknownCurrencyError
+	switch {
+	case errors.As(err, &missingColumns), errors.As(err, &validationError), errors.As(err, &unknownCurrency):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrDatasetNotFound), errors.Is(err, domain.ErrImportJobNotFound),
+		errors.Is(err, domain.ErrCurrencyRateNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrDatasetActive), errors.Is(err, domain.ErrImportJobFinished),
+		errors.Is(err, domain.ErrCurrencyRateExists):
+		status = http.StatusConflict
+	}
+
+	HandleErrorWithStatus(w, status, err.Error(), logger)
+}
+
+func HandleErrorWithStatus(w http.ResponseWriter, status int, message string, logger *logrus.Logger) {
 	errorMessage := response.ErrorMessage{}
 	errorMessage.Errors = append(errorMessage.Errors, message)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
-	err := json.NewEncoder(w).Encode(&errorMessage)
-	if err != nil {
-		logger.Error(err)
-	}
-	return
+	writeJSON(w, status, &errorMessage, logger)
 }
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"github.com/sirupsen/logrus"
+	"mime/multipart"
+	"net/http"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type ExchangeRateHandler struct {
+	exchangeRateService ports.IExchangeRateService
+	logger              *logrus.Logger
+}
+
+var _ ports.IExchangeRateHandler = (*ExchangeRateHandler)(nil)
+
+var currencyRatesTable = &table{
+	columns: []string{"currency", "usdRate", "updatedBy", "updatedAt"},
+	row: func(item interface{}) []interface{} {
+		rate := item.(*domain.CurrencyRate)
+		return []interface{}{rate.Currency, rate.USDRate, rate.UpdatedBy, rate.UpdatedAt.Format(time.RFC3339)}
+	},
+}
+
+var currencyRateChangesTable = &table{
+	columns: []string{"currency", "action", "previousRate", "usdRate", "changedBy", "changedAt"},
+	row: func(item interface{}) []interface{} {
+		change := item.(*domain.CurrencyRateChange)
+		return []interface{}{change.Currency, change.Action, optionalCell(change.PreviousRate), optionalCell(change.USDRate),
+			change.ChangedBy, change.ChangedAt.Format(time.RFC3339)}
+	},
+}
+
+func NewExchangeRateHandler(exchangeRateService ports.IExchangeRateService, logger *logrus.Logger) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRateService,
+		logger,
+	}
+}
+
+// Import stores the ECB-style rates of the "file" part of the multipart form.
+// The optional format query parameter is xml or csv, by default it follows the file extension.
+func (eh ExchangeRateHandler) Import(w http.ResponseWriter, r *http.Request) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		eh.logger.Error(err)
+		HandleError(w, err.Error(), eh.logger)
+		return
+	}
+
+	file, err := nextFilePart(reader)
+	if err != nil {
+		eh.logger.Error(err)
+		HandleError(w, err.Error(), eh.logger)
+		return
+	}
+
+	defer func(file *multipart.Part) {
+		err := file.Close()
+		if err != nil {
+			eh.logger.Error(err)
+		}
+	}(file)
+
+	report, err := eh.exchangeRateService.Import(&request.ExchangeRatesImport{
+		File:     file,
+		FileName: file.FileName(),
+		Format:   r.URL.Query().Get("format"),
+	})
+	if err != nil {
+		eh.logger.Error(err)
+		HandleServiceError(w, err, eh.logger)
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, report, eh.logger)
+}
+
+// Get responds with the rates of the date query parameter, like 2021-06-30, or the current rates without it
+func (eh ExchangeRateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	date, err := queryDate(r, "date")
+	if err != nil {
+		HandleServiceError(w, err, eh.logger)
+		return
+	}
+
+	rates, err := eh.exchangeRateService.GetRates(date)
+	if err != nil {
+		eh.logger.Error(err)
+		HandleServiceError(w, err, eh.logger)
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, rates, eh.logger)
+}
+
+// GetCurrencyRates responds with the currency rates set by admins as JSON, CSV or NDJSON
+func (eh ExchangeRateHandler) GetCurrencyRates(w http.ResponseWriter, r *http.Request) {
+	list, ok := negotiateList(w, r, listMediaTypes, currencyRatesTable, eh.logger)
+	if !ok {
+		return
+	}
+
+	rates, err := eh.exchangeRateService.GetCurrencyRates()
+	if err != nil {
+		eh.logger.Error(err)
+		HandleServiceError(w, err, eh.logger)
+		return
+	}
+
+	list.render(w, func(write func(item interface{}) error) error {
+		for _, rate := range rates {
+			if err := write(rate); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, eh.logger)
+}
+
+// CreateCurrencyRate sets the rate of a currency that has no rate set by admins yet
+func (eh ExchangeRateHandler) CreateCurrencyRate(w http.ResponseWriter, r *http.Request) {
+	rateRequest := request.CurrencyRateRequest{}
+	err := json.NewDecoder(r.Body).Decode(&rateRequest)
+	if err != nil {
+		eh.logger.Error("Error decode in CurrencyRateRequest struct", err)
+		HandleErrorWithStatus(w, http.StatusBadRequest, err.Error(), eh.logger)
+		return
+	}
+
+	rate, err := eh.exchangeRateService.CreateCurrencyRate(&rateRequest, requestUsername(r))
+	if err != nil {
+		HandleServiceError(w, err, eh.logger)
+		return
+	}
+
+	renderJSON(w, r, http.StatusCreated, rate, eh.logger)
+}
+
+// UpdateCurrencyRate replaces the rate of the currency of the path
+func (eh ExchangeRateHandler) UpdateCurrencyRate(w http.ResponseWriter, r *http.Request) {
+	rateRequest := request.CurrencyRateRequest{}
+	err := json.NewDecoder(r.Body).Decode(&rateRequest)
+	if err != nil {
+		eh.logger.Error("Error decode in CurrencyRateRequest struct", err)
+		HandleErrorWithStatus(w, http.StatusBadRequest, err.Error(), eh.logger)
+		return
+	}
+
+	rate, err := eh.exchangeRateService.UpdateCurrencyRate(mux.Vars(r)["currency"], &rateRequest, requestUsername(r))
+	if err != nil {
+		HandleServiceError(w, err, eh.logger)
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, rate, eh.logger)
+}
+
+// DeleteCurrencyRate deletes the rate of the currency of the path, the provider rate is used again
+func (eh ExchangeRateHandler) DeleteCurrencyRate(w http.ResponseWriter, r *http.Request) {
+	err := eh.exchangeRateService.DeleteCurrencyRate(mux.Vars(r)["currency"], requestUsername(r))
+	if err != nil {
+		HandleServiceError(w, err, eh.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCurrencyRateChanges responds with who changed the currency rates and when, newest first.
+// The optional currency query parameter selects the changes of one currency.
+func (eh ExchangeRateHandler) GetCurrencyRateChanges(w http.ResponseWriter, r *http.Request) {
+	list, ok := negotiateList(w, r, listMediaTypes, currencyRateChangesTable, eh.logger)
+	if !ok {
+		return
+	}
+
+	changes, err := eh.exchangeRateService.GetCurrencyRateChanges(r.URL.Query().Get("currency"))
+	if err != nil {
+		HandleServiceError(w, err, eh.logger)
+		return
+	}
+
+	list.render(w, func(write func(item interface{}) error) error {
+		for _, change := range changes {
+			if err := write(change); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, eh.logger)
+}
+
+// queryDate reads the optional date query parameter, it is nil when the parameter is not set
+func queryDate(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if len(value) == 0 {
+		return nil, nil
+	}
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, domain.NewValidationError("%s must be a date like 2021-06-30", name)
+	}
+	return &date, nil
+}
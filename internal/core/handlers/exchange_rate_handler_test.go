@@ -0,0 +1,511 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	mock_ports "github.com/inkoba/app_for_HR/internal/core/ports/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testRatesFile = "Date,USD,PLN\n2021-06-30,1.1884,4.5201\n"
+
+func TestExchangeRateHandler_Import(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIExchangeRateService)
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "rates file is imported",
+			query: "?format=csv",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().Import(gomock.Any()).DoAndReturn(func(ratesImport *request.ExchangeRatesImport) (*response.ExchangeRatesImportReport, error) {
+					content, err := io.ReadAll(ratesImport.File)
+					assert.NoError(t, err)
+					assert.Equal(t, testRatesFile, string(content))
+					assert.Equal(t, "rates.csv", ratesImport.FileName)
+					assert.Equal(t, "csv", ratesImport.Format)
+					return &response.ExchangeRatesImportReport{Days: 1, From: "2021-06-30", To: "2021-06-30", Currencies: []string{"EUR", "PLN", "USD"}}, nil
+				})
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"days":1,"from":"2021-06-30","to":"2021-06-30","currencies":["EUR","PLN","USD"]}
+`,
+		},
+		{
+			name: "get bad request when the file can not be read",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().Import(gomock.Any()).Return(nil, domain.NewValidationError("The exchange rates file can not be read"))
+			},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["The exchange rates file can not be read"]}
+`,
+		},
+		{
+			name: "get error when the database is unavailable",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().Import(gomock.Any()).Return(nil, errors.New("database is unavailable"))
+			},
+			expectedStatusCode: 500,
+			expectedResponseBody: `{"Errors":["database is unavailable"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockIExchangeRateService(c)
+			testCase.mockBehavior(service)
+
+			handler := ExchangeRateHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/exchange-rates", handler.Import).Methods("POST")
+
+			// Create Request
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("file", "rates.csv")
+			assert.NoError(t, err)
+			_, err = part.Write([]byte(testRatesFile))
+			assert.NoError(t, err)
+			assert.NoError(t, writer.Close())
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/exchange-rates"+testCase.query, body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestExchangeRateHandler_Get(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIExchangeRateService)
+
+	date := time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)
+	rates := &domain.ExchangeRates{Date: date, Rates: map[string]float64{"EUR": 1.1884}}
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "rates of the date are returned",
+			query: "?date=2021-06-30",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetRates(&date).Return(rates, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"date":"2021-06-30T00:00:00Z","rates":{"EUR":1.1884}}
+`,
+		},
+		{
+			name: "current rates are returned without a date",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetRates(nil).Return(rates, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"date":"2021-06-30T00:00:00Z","rates":{"EUR":1.1884}}
+`,
+		},
+		{
+			name:               "get bad request when the date is not a date",
+			query:              "?date=30.06.2021",
+			mockBehavior:       func(s *mock_ports.MockIExchangeRateService) {},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["date must be a date like 2021-06-30"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockIExchangeRateService(c)
+			testCase.mockBehavior(service)
+
+			handler := ExchangeRateHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/exchange-rates", handler.Get).Methods("GET")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/exchange-rates"+testCase.query, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestExchangeRateHandler_GetCurrencyRates(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIExchangeRateService)
+
+	updatedAt := time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)
+	rates := []*domain.CurrencyRate{{Currency: "PLN", USDRate: 0.25, UpdatedBy: "admin", UpdatedAt: updatedAt}}
+
+	testTable := []struct {
+		name                 string
+		accept               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "currency rates are returned as JSON",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetCurrencyRates().Return(rates, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `[{"currency":"PLN","usdRate":0.25,"updatedBy":"admin","updatedAt":"2021-06-30T12:00:00Z"}]
+`,
+		},
+		{
+			name:   "currency rates are returned as CSV",
+			accept: "text/csv",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetCurrencyRates().Return(rates, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "currency,usdRate,updatedBy,updatedAt\nPLN,0.25,admin,2021-06-30T12:00:00Z\n",
+		},
+		{
+			name: "get error when the database is unavailable",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetCurrencyRates().Return(nil, errors.New("database is unavailable"))
+			},
+			expectedStatusCode: 500,
+			expectedResponseBody: `{"Errors":["database is unavailable"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockIExchangeRateService(c)
+			testCase.mockBehavior(service)
+
+			handler := ExchangeRateHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/currency-rates", handler.GetCurrencyRates).Methods("GET")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/currency-rates", nil)
+			if len(testCase.accept) > 0 {
+				req.Header.Set("Accept", testCase.accept)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestExchangeRateHandler_CreateCurrencyRate(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIExchangeRateService)
+
+	updatedAt := time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "currency rate is created",
+			inputBody: `{"currency":"PLN","usdRate":0.25}`,
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().CreateCurrencyRate(&request.CurrencyRateRequest{Currency: "PLN", USDRate: 0.25}, gomock.Any()).
+					Return(&domain.CurrencyRate{Currency: "PLN", USDRate: 0.25, UpdatedBy: "admin", UpdatedAt: updatedAt}, nil)
+			},
+			expectedStatusCode: 201,
+			expectedResponseBody: `{"currency":"PLN","usdRate":0.25,"updatedBy":"admin","updatedAt":"2021-06-30T12:00:00Z"}
+`,
+		},
+		{
+			name:      "get conflict when the currency already has a rate",
+			inputBody: `{"currency":"PLN","usdRate":0.25}`,
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().CreateCurrencyRate(gomock.Any(), gomock.Any()).Return(nil, domain.ErrCurrencyRateExists)
+			},
+			expectedStatusCode: 409,
+			expectedResponseBody: `{"Errors":["` + domain.ErrCurrencyRateExists.Error() + `"]}
+`,
+		},
+		{
+			name:      "get bad request when the currency is unknown",
+			inputBody: `{"currency":"ABC","usdRate":0.25}`,
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().CreateCurrencyRate(gomock.Any(), gomock.Any()).Return(nil, &domain.UnknownCurrencyError{Currency: "ABC"})
+			},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["` + (&domain.UnknownCurrencyError{Currency: "ABC"}).Error() + `"]}
+`,
+		},
+		{
+			name:               "get bad request when the body is not JSON",
+			inputBody:          `{"currency":`,
+			mockBehavior:       func(s *mock_ports.MockIExchangeRateService) {},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["unexpected EOF"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockIExchangeRateService(c)
+			testCase.mockBehavior(service)
+
+			handler := ExchangeRateHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/currency-rates", handler.CreateCurrencyRate).Methods("POST")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/currency-rates", bytes.NewBufferString(testCase.inputBody))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestExchangeRateHandler_UpdateCurrencyRate(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIExchangeRateService)
+
+	updatedAt := time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "currency rate is updated",
+			inputBody: `{"usdRate":0.26}`,
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().UpdateCurrencyRate("PLN", &request.CurrencyRateRequest{USDRate: 0.26}, gomock.Any()).
+					Return(&domain.CurrencyRate{Currency: "PLN", USDRate: 0.26, UpdatedBy: "admin", UpdatedAt: updatedAt}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"currency":"PLN","usdRate":0.26,"updatedBy":"admin","updatedAt":"2021-06-30T12:00:00Z"}
+`,
+		},
+		{
+			name:      "get not found when the currency has no rate",
+			inputBody: `{"usdRate":0.26}`,
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().UpdateCurrencyRate("PLN", gomock.Any(), gomock.Any()).Return(nil, domain.ErrCurrencyRateNotFound)
+			},
+			expectedStatusCode: 404,
+			expectedResponseBody: `{"Errors":["` + domain.ErrCurrencyRateNotFound.Error() + `"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockIExchangeRateService(c)
+			testCase.mockBehavior(service)
+
+			handler := ExchangeRateHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/currency-rates/{currency}", handler.UpdateCurrencyRate).Methods("PUT")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/api/currency-rates/PLN", bytes.NewBufferString(testCase.inputBody))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestExchangeRateHandler_DeleteCurrencyRate(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIExchangeRateService)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "currency rate is deleted",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().DeleteCurrencyRate("PLN", gomock.Any()).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name: "get not found when the currency has no rate",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().DeleteCurrencyRate("PLN", gomock.Any()).Return(domain.ErrCurrencyRateNotFound)
+			},
+			expectedStatusCode: 404,
+			expectedResponseBody: `{"Errors":["` + domain.ErrCurrencyRateNotFound.Error() + `"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockIExchangeRateService(c)
+			testCase.mockBehavior(service)
+
+			handler := ExchangeRateHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/currency-rates/{currency}", handler.DeleteCurrencyRate).Methods("DELETE")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/api/currency-rates/PLN", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestExchangeRateHandler_GetCurrencyRateChanges(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIExchangeRateService)
+
+	changedAt := time.Date(2021, 6, 30, 12, 0, 0, 0, time.UTC)
+	previousRate, usdRate := 0.25, 0.26
+
+	testTable := []struct {
+		name                 string
+		query                string
+		accept               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "changes of the currency are returned as CSV",
+			query:  "?currency=PLN",
+			accept: "text/csv",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetCurrencyRateChanges("PLN").Return([]*domain.CurrencyRateChange{
+					{Currency: "PLN", Action: domain.CurrencyRateUpdated, PreviousRate: &previousRate, USDRate: &usdRate, ChangedBy: "admin", ChangedAt: changedAt},
+					{Currency: "PLN", Action: domain.CurrencyRateCreated, USDRate: &previousRate, ChangedBy: "admin", ChangedAt: changedAt.Add(-time.Hour)},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: "currency,action,previousRate,usdRate,changedBy,changedAt\n" +
+				"PLN," + domain.CurrencyRateUpdated + ",0.25,0.26,admin,2021-06-30T12:00:00Z\n" +
+				"PLN," + domain.CurrencyRateCreated + ",,0.25,admin,2021-06-30T11:00:00Z\n",
+		},
+		{
+			name:  "get bad request when the currency is unknown",
+			query: "?currency=ABC",
+			mockBehavior: func(s *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetCurrencyRateChanges("ABC").Return(nil, &domain.UnknownCurrencyError{Currency: "ABC"})
+			},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["` + (&domain.UnknownCurrencyError{Currency: "ABC"}).Error() + `"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockIExchangeRateService(c)
+			testCase.mockBehavior(service)
+
+			handler := ExchangeRateHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/currency-rates/changes", handler.GetCurrencyRateChanges).Methods("GET")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/currency-rates/changes"+testCase.query, nil)
+			if len(testCase.accept) > 0 {
+				req.Header.Set("Accept", testCase.accept)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -3,9 +3,11 @@ package handlers
 import (
 	"encoding/json"
 	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
 	"github.com/inkoba/app_for_HR/internal/core/ports"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"strconv"
 )
 
 type SalaryFilterHandler struct {
@@ -13,6 +15,39 @@ type SalaryFilterHandler struct {
 	logger        *logrus.Logger
 }
 
+var _ ports.IFilterHandler = (*SalaryFilterHandler)(nil)
+
+var salariesTable = &table{
+	columns: []string{
+		"salary", "levelOfSeniority", "yearsTotal", "country",
+		"originalAmount", "originalCurrency", "amount", "currency", "rate", "conversionError",
+	},
+	row: func(item interface{}) []interface{} {
+		salary := item.(*response.SalariesResponse)
+		return []interface{}{
+			salary.Salary, salary.LevelOfSeniority, salary.YearsTotal, salary.Country, salary.OriginalAmount,
+			salary.OriginalCurrency, optionalCell(salary.Amount), salary.Currency, optionalCell(salary.Rate), salary.ConversionError,
+		}
+	},
+}
+
+// exportMediaTypes are the file types of the export, CSV comes first as the default for spreadsheets
+var exportMediaTypes = []string{mediaTypeCSV, mediaTypeXLSX, mediaTypeJSON, mediaTypeNDJSON}
+
+var salaryExportTable = &table{
+	columns: []string{
+		"responseId", "levelOfSeniority", "yearsTotal", "country", "levelOfEnglish",
+		"originalAmount", "originalCurrency", "amount", "currency", "conversionError",
+	},
+	row: func(item interface{}) []interface{} {
+		row := item.(*response.SalaryExportRow)
+		return []interface{}{
+			row.ResponseId, row.LevelOfSeniority, row.YearsTotal, row.Country, row.LevelOfEnglish,
+			row.OriginalAmount, row.OriginalCurrency, optionalCell(row.Amount), row.Currency, row.ConversionError,
+		}
+	},
+}
+
 func NewSalaryFilterHandler(salaryService ports.ISalaryService, logger *logrus.Logger) *SalaryFilterHandler {
 	return &SalaryFilterHandler{
 		salaryService,
@@ -20,25 +55,120 @@ func NewSalaryFilterHandler(salaryService ports.ISalaryService, logger *logrus.L
 	}
 }
 
+// Filter responds with one page of the salaries selected by the filter as JSON, CSV or NDJSON.
+// The JSON page has the total number of the salaries, CSV and NDJSON have it in the X-Total-Count header.
+// The salaries are written as they are read from the database.
 func (fh SalaryFilterHandler) Filter(w http.ResponseWriter, r *http.Request) {
-	salaryFilteringCondition := request.ConditionForFilteringSalaries{}
-	err := json.NewDecoder(r.Body).Decode(&salaryFilteringCondition)
+	list, ok := negotiateList(w, r, listMediaTypes, salariesTable, fh.logger)
+	if !ok {
+		return
+	}
+
+	pageRequest := request.SalaryPageRequest{}
+	err := json.NewDecoder(r.Body).Decode(&pageRequest)
 	if err != nil {
-		fh.logger.Error("Error decode in SalariesResponse struct", err)
+		fh.logger.Error("Error decode in SalaryPageRequest struct", err)
 		HandleError(w, err.Error(), fh.logger)
 		return
 	}
 
-	filteredSalaries, err := fh.salaryService.GetSalariesByFilter(&salaryFilteringCondition)
+	page, err := fh.salaryService.CountSalariesByFilter(&pageRequest)
 	if err != nil {
-		fh.logger.Error("Error getting filtered salaries", err)
-		HandleError(w, err.Error(), fh.logger)
+		fh.logger.Error("Error counting filtered salaries", err)
+		HandleServiceError(w, err, fh.logger)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	list.jsonFields, list.itemsField = page, "salaries"
+	list.render(w, func(write func(item interface{}) error) error {
+		return fh.salaryService.GetSalariesByFilter(&pageRequest, func(salary *response.SalariesResponse) error {
+			return write(salary)
+		})
+	}, fh.logger)
+}
+
+// Stats responds with the statistics of the salaries selected by the filter
+func (fh SalaryFilterHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	statsRequest := request.SalaryStatsRequest{}
+	err := json.NewDecoder(r.Body).Decode(&statsRequest)
+	if err != nil {
+		fh.logger.Error("Error decode in SalaryStatsRequest struct", err)
+		HandleErrorWithStatus(w, http.StatusBadRequest, err.Error(), fh.logger)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(filteredSalaries)
+	stats, err := fh.salaryService.GetSalaryStats(&statsRequest)
 	if err != nil {
-		HandleError(w, err.Error(), fh.logger)
+		fh.logger.Error("Error getting salary statistics", err)
+		HandleServiceError(w, err, fh.logger)
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, stats, fh.logger)
+}
+
+// Groups responds with the statistics of every group of the salaries selected by the filter
+func (fh SalaryFilterHandler) Groups(w http.ResponseWriter, r *http.Request) {
+	groupsRequest := request.SalaryGroupsRequest{}
+	err := json.NewDecoder(r.Body).Decode(&groupsRequest)
+	if err != nil {
+		fh.logger.Error("Error decode in SalaryGroupsRequest struct", err)
+		HandleErrorWithStatus(w, http.StatusBadRequest, err.Error(), fh.logger)
+		return
 	}
+
+	groups, err := fh.salaryService.GetSalaryGroups(&groupsRequest)
+	if err != nil {
+		fh.logger.Error("Error getting salary group statistics", err)
+		HandleServiceError(w, err, fh.logger)
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, groups, fh.logger)
+}
+
+// Histogram responds with the distribution of the salaries selected by the filter
+func (fh SalaryFilterHandler) Histogram(w http.ResponseWriter, r *http.Request) {
+	histogramRequest := request.SalaryHistogramRequest{}
+	err := json.NewDecoder(r.Body).Decode(&histogramRequest)
+	if err != nil {
+		fh.logger.Error("Error decode in SalaryHistogramRequest struct", err)
+		HandleErrorWithStatus(w, http.StatusBadRequest, err.Error(), fh.logger)
+		return
+	}
+
+	histogram, err := fh.salaryService.GetSalaryHistogram(&histogramRequest)
+	if err != nil {
+		fh.logger.Error("Error getting salary histogram", err)
+		HandleServiceError(w, err, fh.logger)
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, histogram, fh.logger)
+}
+
+// Export downloads every salary selected by the filter as a CSV, XLSX, JSON or NDJSON file
+// chosen by the Accept header or the format query parameter, CSV is the default.
+// The file is written as the salaries are read from the database.
+func (fh SalaryFilterHandler) Export(w http.ResponseWriter, r *http.Request) {
+	list, ok := negotiateList(w, r, exportMediaTypes, salaryExportTable, fh.logger)
+	if !ok {
+		return
+	}
+
+	exportRequest := request.SalaryExportRequest{}
+	err := json.NewDecoder(r.Body).Decode(&exportRequest)
+	if err != nil {
+		fh.logger.Error("Error decode in SalaryExportRequest struct", err)
+		HandleErrorWithStatus(w, http.StatusBadRequest, err.Error(), fh.logger)
+		return
+	}
+
+	list.fileName = "salaries"
+	list.render(w, func(write func(item interface{}) error) error {
+		return fh.salaryService.ExportSalaries(&exportRequest, func(row *response.SalaryExportRow) error {
+			return write(row)
+		})
+	}, fh.logger)
 }
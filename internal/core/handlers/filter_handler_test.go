@@ -1,29 +1,61 @@
 package handlers
 
 import (
+	"archive/zip"
 	"bytes"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
 	"github.com/inkoba/app_for_HR/internal/core/domain/request"
 	"github.com/inkoba/app_for_HR/internal/core/domain/response"
 	mock_ports "github.com/inkoba/app_for_HR/internal/core/ports/mocks"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
 func TestSalaryFilterHandler_Filter(t *testing.T) {
-	type mockBehavior func(s *mock_ports.MockISalaryService, salaries *request.ConditionForFilteringSalaries)
+	salaryMin, salaryMax, yearsTotalMin, yearsTotalMax := 2000.0, 4000.0, 3.0, 5.0
+
+	type mockBehavior func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest)
 	testTable := []struct {
 		name                 string
+		accept               string
 		inputBody            string
 		inputCondition       request.ConditionForFilteringSalaries
+		targetCurrency       string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
+		{
+			name:           "salary filter converts the salaries to the target currency",
+			inputBody:      `{"country":"Poland","targetCurrency":"EUR"}`,
+			inputCondition: request.ConditionForFilteringSalaries{Country: "Poland"},
+			targetCurrency: "EUR",
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 1, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).
+					DoAndReturn(streamSalaryResponses(&response.SalariesResponse{
+						Salary:           "2207",
+						LevelOfSeniority: "Middle",
+						YearsTotal:       "3",
+						Country:          "Poland",
+						OriginalAmount:   10000,
+						OriginalCurrency: "PLN",
+						Amount:           floatPointer(2206.92),
+						Currency:         "EUR",
+						Rate:             floatPointer(0.220692)},
+					))
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"total":1,"limit":100,"offset":0,"salaries":[{"salary":"2207","levelOfSeniority":"Middle","yearsTotal":"3","country":"Poland","originalAmount":10000,"originalCurrency":"PLN","amount":2206.92,"currency":"EUR","rate":0.220692}]}
+`,
+		},
 		{
 			name:      "salary filter works successfully when one field is filled",
 			inputBody: `{"country":"","salary":"1500","yearsTotal":"","levelOfSeniority":""}`,
@@ -32,17 +64,23 @@ func TestSalaryFilterHandler_Filter(t *testing.T) {
 				LevelOfSeniority: "",
 				YearsTotal:       "",
 				Country:          ""},
-			mockBehavior: func(s *mock_ports.MockISalaryService, salaries *request.ConditionForFilteringSalaries) {
-				s.EXPECT().GetSalariesByFilter(salaries).
-					Return([]*response.SalariesResponse{{
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 1, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).
+					DoAndReturn(streamSalaryResponses(&response.SalariesResponse{
 						Salary:           "1500",
 						LevelOfSeniority: "Junior",
 						YearsTotal:       "1",
-						Country:          "Belarus"},
-					}, nil)
+						Country:          "Belarus",
+						OriginalAmount:   1500,
+						OriginalCurrency: "USD",
+						Amount:           floatPointer(1500),
+						Currency:         "USD",
+						Rate:             floatPointer(1)},
+					))
 			},
 			expectedStatusCode: 200,
-			expectedResponseBody: `[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus"}]
+			expectedResponseBody: `{"total":1,"limit":100,"offset":0,"salaries":[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus","originalAmount":1500,"originalCurrency":"USD","amount":1500,"currency":"USD","rate":1}]}
 `,
 		},
 		{
@@ -53,16 +91,22 @@ func TestSalaryFilterHandler_Filter(t *testing.T) {
 				LevelOfSeniority: "Junior",
 				YearsTotal:       "",
 				Country:          ""},
-			mockBehavior: func(s *mock_ports.MockISalaryService, salaries *request.ConditionForFilteringSalaries) {
-				s.EXPECT().GetSalariesByFilter(salaries).
-					Return([]*response.SalariesResponse{{
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 1, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).
+					DoAndReturn(streamSalaryResponses(&response.SalariesResponse{
 						Salary:           "1500",
 						LevelOfSeniority: "Junior",
 						YearsTotal:       "1",
-						Country:          "Belarus"}}, nil)
+						Country:          "Belarus",
+						OriginalAmount:   1500,
+						OriginalCurrency: "USD",
+						Amount:           floatPointer(1500),
+						Currency:         "USD",
+						Rate:             floatPointer(1)}))
 			},
 			expectedStatusCode: 200,
-			expectedResponseBody: `[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus"}]
+			expectedResponseBody: `{"total":1,"limit":100,"offset":0,"salaries":[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus","originalAmount":1500,"originalCurrency":"USD","amount":1500,"currency":"USD","rate":1}]}
 `,
 		},
 		{
@@ -73,16 +117,22 @@ func TestSalaryFilterHandler_Filter(t *testing.T) {
 				LevelOfSeniority: "Junior",
 				YearsTotal:       "",
 				Country:          "Belarus"},
-			mockBehavior: func(s *mock_ports.MockISalaryService, salaries *request.ConditionForFilteringSalaries) {
-				s.EXPECT().GetSalariesByFilter(salaries).
-					Return([]*response.SalariesResponse{{
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 1, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).
+					DoAndReturn(streamSalaryResponses(&response.SalariesResponse{
 						Salary:           "1500",
 						LevelOfSeniority: "Junior",
 						YearsTotal:       "1",
-						Country:          "Belarus"}}, nil)
+						Country:          "Belarus",
+						OriginalAmount:   1500,
+						OriginalCurrency: "USD",
+						Amount:           floatPointer(1500),
+						Currency:         "USD",
+						Rate:             floatPointer(1)}))
 			},
 			expectedStatusCode: 200,
-			expectedResponseBody: `[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus"}]
+			expectedResponseBody: `{"total":1,"limit":100,"offset":0,"salaries":[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus","originalAmount":1500,"originalCurrency":"USD","amount":1500,"currency":"USD","rate":1}]}
 `,
 		},
 		{
@@ -93,17 +143,23 @@ func TestSalaryFilterHandler_Filter(t *testing.T) {
 				LevelOfSeniority: "Junior",
 				YearsTotal:       "1",
 				Country:          "Belarus"},
-			mockBehavior: func(s *mock_ports.MockISalaryService, salaries *request.ConditionForFilteringSalaries) {
-				s.EXPECT().GetSalariesByFilter(salaries).
-					Return([]*response.SalariesResponse{{
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 1, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).
+					DoAndReturn(streamSalaryResponses(&response.SalariesResponse{
 						Salary:           "1500",
 						LevelOfSeniority: "Junior",
 						YearsTotal:       "1",
-						Country:          "Belarus"},
-					}, nil)
+						Country:          "Belarus",
+						OriginalAmount:   1500,
+						OriginalCurrency: "USD",
+						Amount:           floatPointer(1500),
+						Currency:         "USD",
+						Rate:             floatPointer(1)},
+					))
 			},
 			expectedStatusCode: 200,
-			expectedResponseBody: `[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus"}]
+			expectedResponseBody: `{"total":1,"limit":100,"offset":0,"salaries":[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus","originalAmount":1500,"originalCurrency":"USD","amount":1500,"currency":"USD","rate":1}]}
 `,
 		},
 		{
@@ -114,23 +170,34 @@ func TestSalaryFilterHandler_Filter(t *testing.T) {
 				LevelOfSeniority: "",
 				YearsTotal:       "",
 				Country:          ""},
-			mockBehavior: func(s *mock_ports.MockISalaryService, salaries *request.ConditionForFilteringSalaries) {
-				s.EXPECT().GetSalariesByFilter(salaries).
-					Return([]*response.SalariesResponse{{
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 2, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).
+					DoAndReturn(streamSalaryResponses(&response.SalariesResponse{
 						Salary:           "1500",
 						LevelOfSeniority: "Junior",
 						YearsTotal:       "1",
 						Country:          "Belarus",
+						OriginalAmount:   1500,
+						OriginalCurrency: "USD",
+						Amount:           floatPointer(1500),
+						Currency:         "USD",
+						Rate:             floatPointer(1),
 					},
-						{
+						&response.SalariesResponse{
 							Salary:           "1500",
 							LevelOfSeniority: "Junior",
 							YearsTotal:       "1",
-							Country:          "Belarus"},
-					}, nil)
+							Country:          "Belarus",
+							OriginalAmount:   1500,
+							OriginalCurrency: "USD",
+							Amount:           floatPointer(1500),
+							Currency:         "USD",
+							Rate:             floatPointer(1)},
+					))
 			},
 			expectedStatusCode: 200,
-			expectedResponseBody: `[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus"},{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus"}]
+			expectedResponseBody: `{"total":2,"limit":100,"offset":0,"salaries":[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus","originalAmount":1500,"originalCurrency":"USD","amount":1500,"currency":"USD","rate":1},{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus","originalAmount":1500,"originalCurrency":"USD","amount":1500,"currency":"USD","rate":1}]}
 `,
 		},
 		{
@@ -141,32 +208,197 @@ func TestSalaryFilterHandler_Filter(t *testing.T) {
 				LevelOfSeniority: "",
 				YearsTotal:       "",
 				Country:          "   "},
-			mockBehavior: func(s *mock_ports.MockISalaryService, salaries *request.ConditionForFilteringSalaries) {
-				s.EXPECT().GetSalariesByFilter(salaries).
-					Return([]*response.SalariesResponse{{
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 2, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).
+					DoAndReturn(streamSalaryResponses(&response.SalariesResponse{
 						Salary:           "1500",
 						LevelOfSeniority: "Junior",
 						YearsTotal:       "1",
 						Country:          "Belarus",
+						OriginalAmount:   1500,
+						OriginalCurrency: "USD",
+						Amount:           floatPointer(1500),
+						Currency:         "USD",
+						Rate:             floatPointer(1),
 					},
-						{
+						&response.SalariesResponse{
 							Salary:           "1500",
 							LevelOfSeniority: "Junior",
 							YearsTotal:       "1",
-							Country:          "Belarus"},
-					}, nil)
+							Country:          "Belarus",
+							OriginalAmount:   1500,
+							OriginalCurrency: "USD",
+							Amount:           floatPointer(1500),
+							Currency:         "USD",
+							Rate:             floatPointer(1)},
+					))
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"total":2,"limit":100,"offset":0,"salaries":[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus","originalAmount":1500,"originalCurrency":"USD","amount":1500,"currency":"USD","rate":1},{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus","originalAmount":1500,"originalCurrency":"USD","amount":1500,"currency":"USD","rate":1}]}
+`,
+		},
+		{
+			name:      "salary filter works successfully with salary and years ranges",
+			inputBody: `{"salaryMin":2000,"salaryMax":4000,"salaryCurrency":"EUR","yearsTotalMin":3,"yearsTotalMax":5}`,
+			inputCondition: request.ConditionForFilteringSalaries{
+				SalaryMin:      &salaryMin,
+				SalaryMax:      &salaryMax,
+				SalaryCurrency: "EUR",
+				YearsTotalMin:  &yearsTotalMin,
+				YearsTotalMax:  &yearsTotalMax,
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 1, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).
+					DoAndReturn(streamSalaryResponses(&response.SalariesResponse{
+						Salary:           "3398",
+						LevelOfSeniority: "Senior",
+						YearsTotal:       "5",
+						Country:          "Latvia",
+						OriginalAmount:   3000,
+						OriginalCurrency: "EUR",
+						Amount:           floatPointer(3398.4),
+						Currency:         "USD",
+						Rate:             floatPointer(1.1328)},
+					))
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"total":1,"limit":100,"offset":0,"salaries":[{"salary":"3398","levelOfSeniority":"Senior","yearsTotal":"5","country":"Latvia","originalAmount":3000,"originalCurrency":"EUR","amount":3398.4,"currency":"USD","rate":1.1328}]}
+`,
+		},
+		{
+			name:      "salary filter works successfully with value lists and exclusions",
+			inputBody: `{"countries":{"in":["Poland","Lithuania","Latvia"]},"levelsOfSeniority":{"in":["middle","senior"]},"levelsOfEnglish":{"notIn":["A1"]},"levelOfEnglish":"b2"}`,
+			inputCondition: request.ConditionForFilteringSalaries{
+				Countries:         &request.ValuesFilter{In: []string{"Poland", "Lithuania", "Latvia"}},
+				LevelsOfSeniority: &request.ValuesFilter{In: []string{"middle", "senior"}},
+				LevelOfEnglish:    "b2",
+				LevelsOfEnglish:   &request.ValuesFilter{NotIn: []string{"A1"}},
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 1, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).
+					DoAndReturn(streamSalaryResponses(&response.SalariesResponse{
+						Salary:           "2500",
+						LevelOfSeniority: "Middle",
+						YearsTotal:       "3",
+						Country:          "Poland",
+						OriginalAmount:   2500,
+						OriginalCurrency: "USD",
+						Amount:           floatPointer(2500),
+						Currency:         "USD",
+						Rate:             floatPointer(1)},
+					))
 			},
 			expectedStatusCode: 200,
-			expectedResponseBody: `[{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus"},{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus"}]
+			expectedResponseBody: `{"total":1,"limit":100,"offset":0,"salaries":[{"salary":"2500","levelOfSeniority":"Middle","yearsTotal":"3","country":"Poland","originalAmount":2500,"originalCurrency":"USD","amount":2500,"currency":"USD","rate":1}]}
+`,
+		},
+		{
+			name:      "get bad request when the salary range is reversed",
+			inputBody: `{"salaryMin":4000,"salaryMax":2000}`,
+			inputCondition: request.ConditionForFilteringSalaries{
+				SalaryMin: &salaryMax,
+				SalaryMax: &salaryMin,
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).
+					Return(nil, domain.NewValidationError("salaryMin must not be greater than salaryMax"))
+			},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["salaryMin must not be greater than salaryMax"]}
+`,
+		},
+		{
+			name:      "salary filter returns an empty page after the last salary",
+			inputBody: `{"country":"Belarus","limit":10,"offset":20,"sortBy":"salary","sortOrder":"desc"}`,
+			inputCondition: request.ConditionForFilteringSalaries{
+				Country: "Belarus",
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				page.Limit, page.Offset, page.SortBy, page.SortOrder = 10, 20, "salary", "desc"
+				s.EXPECT().CountSalariesByFilter(page).
+					Return(&response.SalariesPage{Total: 12, Limit: 10, Offset: 20, SortBy: "salary", SortOrder: "desc"}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).DoAndReturn(streamSalaryResponses())
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"total":12,"limit":10,"offset":20,"sortBy":"salary","sortOrder":"desc","salaries":[]}
+`,
+		},
+		{
+			name:      "salary filter page is returned as CSV with the total in a header",
+			accept:    "text/csv",
+			inputBody: `{"country":"Belarus"}`,
+			inputCondition: request.ConditionForFilteringSalaries{
+				Country: "Belarus",
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 42, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).DoAndReturn(streamSalaryResponses(
+					&response.SalariesResponse{Salary: "1500", LevelOfSeniority: "Junior", YearsTotal: "1", Country: "Belarus", OriginalAmount: 1500, OriginalCurrency: "USD", Amount: floatPointer(1500), Currency: "USD", Rate: floatPointer(1)},
+					&response.SalariesResponse{Salary: "2500", LevelOfSeniority: "Middle", YearsTotal: "3.5", Country: "Belarus", OriginalAmount: 2500, OriginalCurrency: "USD", Amount: floatPointer(2500), Currency: "USD", Rate: floatPointer(1)},
+				))
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `salary,levelOfSeniority,yearsTotal,country,originalAmount,originalCurrency,amount,currency,rate,conversionError
+1500,Junior,1,Belarus,1500,USD,1500,USD,1,
+2500,Middle,3.5,Belarus,2500,USD,2500,USD,1,
+`,
+		},
+		{
+			name:      "salary filter page is returned as NDJSON",
+			accept:    "application/x-ndjson",
+			inputBody: `{"country":"Belarus"}`,
+			inputCondition: request.ConditionForFilteringSalaries{
+				Country: "Belarus",
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 1, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).DoAndReturn(streamSalaryResponses(
+					&response.SalariesResponse{Salary: "1500", LevelOfSeniority: "Junior", YearsTotal: "1", Country: "Belarus", OriginalAmount: 1500, OriginalCurrency: "USD", Amount: floatPointer(1500), Currency: "USD", Rate: floatPointer(1)},
+				))
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"salary":"1500","levelOfSeniority":"Junior","yearsTotal":"1","country":"Belarus","originalAmount":1500,"originalCurrency":"USD","amount":1500,"currency":"USD","rate":1}
+`,
+		},
+		{
+			name:      "salary that can not be converted is returned without an amount",
+			accept:    "text/csv",
+			inputBody: `{"country":"Belarus","rateDate":"today"}`,
+			inputCondition: request.ConditionForFilteringSalaries{
+				Country:  "Belarus",
+				RateDate: "today",
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 1, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).DoAndReturn(streamSalaryResponses(
+					&response.SalariesResponse{LevelOfSeniority: "Junior", YearsTotal: "1", Country: "Belarus", OriginalAmount: 3000, OriginalCurrency: "BYN", Currency: "USD",
+						ConversionError: "The salary 3000 BYN can not be converted to USD: there is no exchange rate for the currency"},
+				))
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `salary,levelOfSeniority,yearsTotal,country,originalAmount,originalCurrency,amount,currency,rate,conversionError
+,Junior,1,Belarus,3000,BYN,,USD,,The salary 3000 BYN can not be converted to USD: there is no exchange rate for the currency
+`,
+		},
+		{
+			name:               "salary filter is not acceptable as XML",
+			accept:             "application/xml",
+			inputBody:          `{"country":"Belarus"}`,
+			mockBehavior:       func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {},
+			expectedStatusCode: 406,
+			expectedResponseBody: `{"Errors":["The response can only be one of: application/json, text/csv, application/x-ndjson"]}
 `,
 		},
 		{
 			name:               "salary filter has no string value in the country field then an error follows",
 			inputBody:          `{"country":1213,"salary":"","yearsTotal":"","levelOfSeniority":""}`,
 			inputCondition:     request.ConditionForFilteringSalaries{},
-			mockBehavior:       func(s *mock_ports.MockISalaryService, salaries *request.ConditionForFilteringSalaries) {},
+			mockBehavior:       func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {},
 			expectedStatusCode: 500,
-			expectedResponseBody: `{"Errors":["json: cannot unmarshal number into Go struct field ConditionForFilteringSalaries.country of type string"]}
+			expectedResponseBody: `{"Errors":["json: cannot unmarshal number into Go struct field SalaryPageRequest.country of type string"]}
 `,
 		},
 		{
@@ -178,9 +410,10 @@ func TestSalaryFilterHandler_Filter(t *testing.T) {
 				YearsTotal:       "",
 				Country:          "Belarus",
 			},
-			mockBehavior: func(s *mock_ports.MockISalaryService, salaries *request.ConditionForFilteringSalaries) {
-				s.EXPECT().GetSalariesByFilter(salaries).
-					Return(nil, errors.New("Error getting filtered salaries"))
+			mockBehavior: func(s *mock_ports.MockISalaryService, page *request.SalaryPageRequest) {
+				s.EXPECT().CountSalariesByFilter(page).Return(&response.SalariesPage{Total: 1, Limit: 100}, nil)
+				s.EXPECT().GetSalariesByFilter(page, gomock.Any()).
+					Return(errors.New("Error getting filtered salaries"))
 			},
 			expectedStatusCode: 500,
 			expectedResponseBody: `{"Errors":["Error getting filtered salaries"]}
@@ -194,7 +427,8 @@ func TestSalaryFilterHandler_Filter(t *testing.T) {
 			defer c.Finish()
 
 			service := mock_ports.NewMockISalaryService(c)
-			testCase.mockBehavior(service, &testCase.inputCondition)
+			pageRequest := request.SalaryPageRequest{ConditionForFilteringSalaries: testCase.inputCondition, TargetCurrency: testCase.targetCurrency}
+			testCase.mockBehavior(service, &pageRequest)
 
 			handler := SalaryFilterHandler{service, logrus.New()}
 
@@ -206,6 +440,101 @@ func TestSalaryFilterHandler_Filter(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("POST", "/api/filter",
 				bytes.NewBufferString(testCase.inputBody))
+			if len(testCase.accept) > 0 {
+				req.Header.Set("Accept", testCase.accept)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+			if testCase.expectedStatusCode == 200 {
+				assert.NotEmpty(t, w.Header().Get("X-Total-Count"))
+			}
+		})
+	}
+}
+
+func TestSalaryFilterHandler_Stats(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryService, statsRequest *request.SalaryStatsRequest)
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		inputRequest         request.SalaryStatsRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "statistics are returned for the filter",
+			inputBody: `{"countries":{"in":["Poland","Latvia"]},"targetCurrency":"EUR"}`,
+			inputRequest: request.SalaryStatsRequest{
+				ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+					Countries: &request.ValuesFilter{In: []string{"Poland", "Latvia"}},
+				},
+				TargetCurrency: "EUR",
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, statsRequest *request.SalaryStatsRequest) {
+				s.EXPECT().GetSalaryStats(statsRequest).Return(&response.SalaryStatsResponse{
+					BaseCurrency: "USD", Currency: "EUR", Rate: 0.882768, Count: 3, Min: 1000, Max: 3000, Mean: 2000, Median: 2000, P10: 1200, P25: 1500, P75: 2500, P90: 2800,
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"baseCurrency":"USD","currency":"EUR","rate":0.882768,"count":3,"min":1000,"max":3000,"mean":2000,"median":2000,"p10":1200,"p25":1500,"p75":2500,"p90":2800}
+`,
+		},
+		{
+			name:               "get bad request when the body is not valid",
+			inputBody:          `{"targetCurrency":1}`,
+			mockBehavior:       func(s *mock_ports.MockISalaryService, statsRequest *request.SalaryStatsRequest) {},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["json: cannot unmarshal number into Go struct field SalaryStatsRequest.targetCurrency of type string"]}
+`,
+		},
+		{
+			name:         "get bad request when the currency is unknown",
+			inputBody:    `{"targetCurrency":"GBP"}`,
+			inputRequest: request.SalaryStatsRequest{TargetCurrency: "GBP"},
+			mockBehavior: func(s *mock_ports.MockISalaryService, statsRequest *request.SalaryStatsRequest) {
+				s.EXPECT().GetSalaryStats(statsRequest).Return(nil, domain.NewValidationError("Currency %s can not be converted to USD", "GBP"))
+			},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["Currency GBP can not be converted to USD"]}
+`,
+		},
+		{
+			name:         "get error when the database is unavailable",
+			inputBody:    `{}`,
+			inputRequest: request.SalaryStatsRequest{},
+			mockBehavior: func(s *mock_ports.MockISalaryService, statsRequest *request.SalaryStatsRequest) {
+				s.EXPECT().GetSalaryStats(statsRequest).Return(nil, errors.New("database is unavailable"))
+			},
+			expectedStatusCode: 500,
+			expectedResponseBody: `{"Errors":["database is unavailable"]}
+`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockISalaryService(c)
+			testCase.mockBehavior(service, &testCase.inputRequest)
+
+			handler := SalaryFilterHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/salaries/stats", handler.Stats).Methods("POST")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/salaries/stats",
+				bytes.NewBufferString(testCase.inputBody))
 
 			// Make Request
 			r.ServeHTTP(w, req)
@@ -216,3 +545,354 @@ func TestSalaryFilterHandler_Filter(t *testing.T) {
 		})
 	}
 }
+
+func TestSalaryFilterHandler_Groups(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryService, groupsRequest *request.SalaryGroupsRequest)
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		inputRequest         request.SalaryGroupsRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "group statistics are returned for the filter",
+			inputBody: `{"groupBy":["levelOfSeniority","country"],"countries":{"in":["Poland"]}}`,
+			inputRequest: request.SalaryGroupsRequest{
+				SalaryStatsRequest: request.SalaryStatsRequest{
+					ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+						Countries: &request.ValuesFilter{In: []string{"Poland"}},
+					},
+				},
+				GroupBy: []string{"levelOfSeniority", "country"},
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, groupsRequest *request.SalaryGroupsRequest) {
+				s.EXPECT().GetSalaryGroups(groupsRequest).Return(&response.SalaryGroupsResponse{
+					GroupBy:          []string{"levelOfSeniority", "country"},
+					MinGroupSize:     5,
+					SuppressedGroups: 1,
+					Groups: []*response.SalaryGroupResponse{{
+						Group: map[string]interface{}{"levelOfSeniority": "Middle", "country": "Poland"},
+						SalaryStatsResponse: response.SalaryStatsResponse{
+							BaseCurrency: "USD", Currency: "USD", Rate: 1, Count: 6, Min: 1000, Max: 3000, Mean: 2000, Median: 2000, P10: 1000, P25: 1500, P75: 2500, P90: 3000,
+						},
+					}},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"groupBy":["levelOfSeniority","country"],"minGroupSize":5,"suppressedGroups":1,"groups":[{"group":{"country":"Poland","levelOfSeniority":"Middle"},"baseCurrency":"USD","currency":"USD","rate":1,"count":6,"min":1000,"max":3000,"mean":2000,"median":2000,"p10":1000,"p25":1500,"p75":2500,"p90":3000}]}
+`,
+		},
+		{
+			name:      "get bad request when the dimension is unknown",
+			inputBody: `{"groupBy":["salary"]}`,
+			inputRequest: request.SalaryGroupsRequest{
+				GroupBy: []string{"salary"},
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, groupsRequest *request.SalaryGroupsRequest) {
+				s.EXPECT().GetSalaryGroups(groupsRequest).
+					Return(nil, domain.NewValidationError("Salaries can not be grouped by %s", "salary"))
+			},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["Salaries can not be grouped by salary"]}
+`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockISalaryService(c)
+			testCase.mockBehavior(service, &testCase.inputRequest)
+
+			handler := SalaryFilterHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/salaries/groups", handler.Groups).Methods("POST")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/salaries/groups",
+				bytes.NewBufferString(testCase.inputBody))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestSalaryFilterHandler_Histogram(t *testing.T) {
+	bucketWidth, negativeBucketWidth := 1000.0, -5.0
+
+	type mockBehavior func(s *mock_ports.MockISalaryService, histogramRequest *request.SalaryHistogramRequest)
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		inputRequest         request.SalaryHistogramRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "histogram is returned for the filter",
+			inputBody: `{"country":"Poland","targetCurrency":"EUR","bucketWidth":1000}`,
+			inputRequest: request.SalaryHistogramRequest{
+				SalaryStatsRequest: request.SalaryStatsRequest{
+					ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{Country: "Poland"},
+					TargetCurrency:                "EUR",
+				},
+				BucketWidth: &bucketWidth,
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, histogramRequest *request.SalaryHistogramRequest) {
+				s.EXPECT().GetSalaryHistogram(histogramRequest).Return(&response.SalaryHistogramResponse{
+					BaseCurrency: "USD",
+					Currency:     "EUR",
+					Rate:         0.882768,
+					Count:        6,
+					BucketWidth:  1000,
+					Buckets: []*response.HistogramBucketResponse{
+						{From: 1000, To: 2000, Count: 2},
+						{From: 2000, To: 3000, Count: 3},
+					},
+					Outliers: response.HistogramOutliersResponse{LowerBound: 1000, UpperBound: 2800, Above: 1},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"baseCurrency":"USD","currency":"EUR","rate":0.882768,"count":6,"bucketWidth":1000,"buckets":[{"from":1000,"to":2000,"count":2},{"from":2000,"to":3000,"count":3}],"outliers":{"lowerBound":1000,"upperBound":2800,"below":0,"above":1}}
+`,
+		},
+		{
+			name:      "get bad request when the bucket width is not positive",
+			inputBody: `{"bucketWidth":-5}`,
+			inputRequest: request.SalaryHistogramRequest{
+				BucketWidth: &negativeBucketWidth,
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, histogramRequest *request.SalaryHistogramRequest) {
+				s.EXPECT().GetSalaryHistogram(histogramRequest).
+					Return(nil, domain.NewValidationError("bucketWidth must be greater than 0"))
+			},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["bucketWidth must be greater than 0"]}
+`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockISalaryService(c)
+			testCase.mockBehavior(service, &testCase.inputRequest)
+
+			handler := SalaryFilterHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/salaries/histogram", handler.Histogram).Methods("POST")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/salaries/histogram",
+				bytes.NewBufferString(testCase.inputBody))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestSalaryFilterHandler_Export(t *testing.T) {
+	rows := []*response.SalaryExportRow{
+		{ResponseId: "17", LevelOfSeniority: "Middle", YearsTotal: 3.5, Country: "Poland", LevelOfEnglish: "B2", OriginalAmount: 9000, OriginalCurrency: "PLN", Amount: floatPointer(2000), Currency: "EUR"},
+		{ResponseId: "18", LevelOfSeniority: "Junior, trainee", YearsTotal: 1, Country: "Poland", LevelOfEnglish: "B1", OriginalAmount: 1800, OriginalCurrency: "EUR", Amount: floatPointer(1800), Currency: "EUR"},
+	}
+
+	type mockBehavior func(s *mock_ports.MockISalaryService, exportRequest *request.SalaryExportRequest)
+	testTable := []struct {
+		name                 string
+		format               string
+		inputBody            string
+		inputRequest         request.SalaryExportRequest
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name:      "salaries are exported as CSV by default",
+			inputBody: `{"country":"Poland","targetCurrency":"EUR"}`,
+			inputRequest: request.SalaryExportRequest{
+				ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{Country: "Poland"},
+				TargetCurrency:                "EUR",
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, exportRequest *request.SalaryExportRequest) {
+				s.EXPECT().ExportSalaries(exportRequest, gomock.Any()).DoAndReturn(streamExportRows(rows...))
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "text/csv",
+			expectedResponseBody: `responseId,levelOfSeniority,yearsTotal,country,levelOfEnglish,originalAmount,originalCurrency,amount,currency,conversionError
+17,Middle,3.5,Poland,B2,9000,PLN,2000,EUR,
+18,"Junior, trainee",1,Poland,B1,1800,EUR,1800,EUR,
+`,
+		},
+		{
+			name:      "empty CSV export has the header row",
+			format:    "csv",
+			inputBody: `{"country":"Narnia"}`,
+			inputRequest: request.SalaryExportRequest{
+				ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{Country: "Narnia"},
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, exportRequest *request.SalaryExportRequest) {
+				s.EXPECT().ExportSalaries(exportRequest, gomock.Any()).DoAndReturn(streamExportRows())
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "text/csv",
+			expectedResponseBody: `responseId,levelOfSeniority,yearsTotal,country,levelOfEnglish,originalAmount,originalCurrency,amount,currency,conversionError
+`,
+		},
+		{
+			name:         "salaries are exported as JSON",
+			format:       "json",
+			inputBody:    `{}`,
+			inputRequest: request.SalaryExportRequest{},
+			mockBehavior: func(s *mock_ports.MockISalaryService, exportRequest *request.SalaryExportRequest) {
+				s.EXPECT().ExportSalaries(exportRequest, gomock.Any()).DoAndReturn(streamExportRows(rows[0]))
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "application/json",
+			expectedResponseBody: `[{"responseId":"17","levelOfSeniority":"Middle","yearsTotal":3.5,"country":"Poland","levelOfEnglish":"B2","originalAmount":9000,"originalCurrency":"PLN","amount":2000,"currency":"EUR"}]
+`,
+		},
+		{
+			name:                "get bad request when the format is unknown",
+			format:              "pdf",
+			inputBody:           `{}`,
+			mockBehavior:        func(s *mock_ports.MockISalaryService, exportRequest *request.SalaryExportRequest) {},
+			expectedStatusCode:  400,
+			expectedContentType: "application/json",
+			expectedResponseBody: `{"Errors":["Format \"pdf\" is not supported, use one of: csv, xlsx, json, ndjson"]}
+`,
+		},
+		{
+			name:      "get bad request when the currency is unknown",
+			inputBody: `{"targetCurrency":"GBP"}`,
+			inputRequest: request.SalaryExportRequest{
+				TargetCurrency: "GBP",
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryService, exportRequest *request.SalaryExportRequest) {
+				s.EXPECT().ExportSalaries(exportRequest, gomock.Any()).
+					Return(domain.NewValidationError("Currency GBP can not be converted to USD"))
+			},
+			expectedStatusCode:  400,
+			expectedContentType: "application/json",
+			expectedResponseBody: `{"Errors":["Currency GBP can not be converted to USD"]}
+`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockISalaryService(c)
+			testCase.mockBehavior(service, &testCase.inputRequest)
+
+			handler := SalaryFilterHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/salaries/export", handler.Export).Methods("POST")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/salaries/export?format="+testCase.format,
+				bytes.NewBufferString(testCase.inputBody))
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Header().Get("Content-Type"), testCase.expectedContentType)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestSalaryFilterHandler_ExportXLSX(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	service := mock_ports.NewMockISalaryService(c)
+	service.EXPECT().ExportSalaries(&request.SalaryExportRequest{}, gomock.Any()).DoAndReturn(streamExportRows(
+		&response.SalaryExportRow{ResponseId: "17", LevelOfSeniority: "R&D lead", YearsTotal: 3.5, Country: "Poland", LevelOfEnglish: "B2", OriginalAmount: 9000, OriginalCurrency: "PLN", Amount: floatPointer(2000), Currency: "EUR"},
+	))
+	handler := SalaryFilterHandler{service, logrus.New()}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/salaries/export?format=xlsx", bytes.NewBufferString(`{}`))
+	handler.Export(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `attachment; filename="salaries.xlsx"`, w.Header().Get("Content-Disposition"))
+
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	assert.NoError(t, err)
+	var names []string
+	var sheet []byte
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			content, err := file.Open()
+			assert.NoError(t, err)
+			sheet, err = io.ReadAll(content)
+			assert.NoError(t, err)
+		}
+	}
+	assert.Equal(t, []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}, names)
+	assert.Contains(t, string(sheet), `<row><c t="inlineStr"><is><t>responseId</t></is></c>`)
+	assert.Contains(t, string(sheet), `<c t="inlineStr"><is><t>R&amp;D lead</t></is></c><c><v>3.5</v></c>`)
+	assert.True(t, strings.HasSuffix(string(sheet), "</row></sheetData></worksheet>"))
+}
+
+// streamExportRows passes the rows to the callback of ExportSalaries like the service streams them
+func streamExportRows(rows ...*response.SalaryExportRow) func(*request.SalaryExportRequest, func(*response.SalaryExportRow) error) error {
+	return func(_ *request.SalaryExportRequest, each func(*response.SalaryExportRow) error) error {
+		for _, row := range rows {
+			if err := each(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// streamSalaryResponses passes the salaries to the callback of GetSalariesByFilter like the service streams them
+func streamSalaryResponses(salaries ...*response.SalariesResponse) func(*request.SalaryPageRequest, func(*response.SalariesResponse) error) error {
+	return func(_ *request.SalaryPageRequest, each func(*response.SalariesResponse) error) error {
+		for _, salary := range salaries {
+			if err := each(salary); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func floatPointer(value float64) *float64 {
+	return &value
+}
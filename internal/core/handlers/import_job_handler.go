@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type ImportJobHandler struct {
+	importJobService ports.IImportJobService
+	logger           *logrus.Logger
+}
+
+var _ ports.IImportJobHandler = (*ImportJobHandler)(nil)
+
+var rowErrorMediaTypes = []string{mediaTypeCSV, mediaTypeJSON, mediaTypeNDJSON}
+
+var rowErrorsTable = &table{
+	columns: []string{"line", "column", "value", "code", "message"},
+	row: func(item interface{}) []interface{} {
+		rowError := item.(*response.RowError)
+		return []interface{}{rowError.Line, rowError.Column, rowError.Value, rowError.Code, rowError.Message}
+	},
+}
+
+func NewImportJobHandler(importJobService ports.IImportJobService, logger *logrus.Logger) *ImportJobHandler {
+	return &ImportJobHandler{
+		importJobService,
+		logger,
+	}
+}
+
+func (jh ImportJobHandler) Get(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	job, err := jh.importJobService.Get(id)
+	if err != nil {
+		jh.logger.Error(err)
+		HandleServiceError(w, err, jh.logger)
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, job, jh.logger)
+}
+
+func (jh ImportJobHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	job, err := jh.importJobService.Cancel(id)
+	if err != nil {
+		jh.logger.Error(err)
+		HandleServiceError(w, err, jh.logger)
+		return
+	}
+
+	renderJSON(w, r, http.StatusAccepted, job, jh.logger)
+}
+
+// GetErrors downloads the row errors of the import job as a CSV file, or as JSON or NDJSON when the request accepts them
+func (jh ImportJobHandler) GetErrors(w http.ResponseWriter, r *http.Request) {
+	list, ok := negotiateList(w, r, rowErrorMediaTypes, rowErrorsTable, jh.logger)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	job, err := jh.importJobService.Get(id)
+	if err != nil {
+		jh.logger.Error(err)
+		HandleServiceError(w, err, jh.logger)
+		return
+	}
+
+	list.fileName = "import-" + job.Id + "-errors"
+	list.render(w, func(write func(item interface{}) error) error {
+		for _, rowError := range job.Errors {
+			if err := write(rowError); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, jh.logger)
+}
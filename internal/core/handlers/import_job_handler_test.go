@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	mock_ports "github.com/inkoba/app_for_HR/internal/core/ports/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+var testRowErrors = []*response.RowError{
+	{Line: 5, Column: domain.ColumnSalary, Value: "1000", Code: response.RowErrorInvalidSalary, Message: "The salary must be an amount followed by a currency"},
+	{Line: 7, Code: response.RowErrorInvalidRow, Message: "bare \" in non-quoted-field"},
+}
+
+func TestImportJobHandler_Get(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIImportJobService, jobId string)
+	testTable := []struct {
+		name                 string
+		inputId              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "job progress is returned",
+			inputId: "3d624904890861643c610064",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, jobId string) {
+				s.EXPECT().Get(jobId).Return(&domain.ImportJob{
+					Id:            jobId,
+					Status:        domain.ImportJobStatusRunning,
+					Mode:          request.UploadModeReplace,
+					RowsProcessed: 2000,
+					RowsSkipped:   1,
+					Errors:        testRowErrors,
+					CreatedAt:     testDatasetCreatedAt,
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"id":"3d624904890861643c610064","status":"running","mode":"replace","rowsProcessed":2000,"rowsSkipped":1,"errors":[{"Line":5,"Column":"salary","Value":"1000","Code":"invalid_salary","Message":"The salary must be an amount followed by a currency"},{"Line":7,"Code":"invalid_row","Message":"bare \" in non-quoted-field"}],"createdAt":"2022-05-01T10:00:00Z"}
+`,
+		},
+		{
+			name:    "get not found when the job does not exist",
+			inputId: "3d624904890861643c610064",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, jobId string) {
+				s.EXPECT().Get(jobId).Return(nil, domain.ErrImportJobNotFound)
+			},
+			expectedStatusCode: 404,
+			expectedResponseBody: `{"Errors":["Import job is not found"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockIImportJobService(c)
+			testCase.mockBehavior(service, testCase.inputId)
+
+			handler := ImportJobHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/imports/{id:[a-zA-Z0-9]*}", handler.Get).Methods("GET")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/imports/"+testCase.inputId, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestImportJobHandler_Cancel(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIImportJobService, jobId string)
+	testTable := []struct {
+		name                 string
+		inputId              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "job cancellation is accepted",
+			inputId: "3d624904890861643c610064",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, jobId string) {
+				s.EXPECT().Cancel(jobId).Return(&domain.ImportJob{
+					Id:        jobId,
+					Status:    domain.ImportJobStatusRunning,
+					CreatedAt: testDatasetCreatedAt,
+				}, nil)
+			},
+			expectedStatusCode: 202,
+			expectedResponseBody: `{"id":"3d624904890861643c610064","status":"running","rowsProcessed":0,"rowsSkipped":0,"errors":null,"createdAt":"2022-05-01T10:00:00Z"}
+`,
+		},
+		{
+			name:    "get conflict when the job is already finished",
+			inputId: "3d624904890861643c610064",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, jobId string) {
+				s.EXPECT().Cancel(jobId).Return(nil, domain.ErrImportJobFinished)
+			},
+			expectedStatusCode: 409,
+			expectedResponseBody: `{"Errors":["Import job is already finished"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockIImportJobService(c)
+			testCase.mockBehavior(service, testCase.inputId)
+
+			handler := ImportJobHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/imports/{id:[a-zA-Z0-9]*}/cancel", handler.Cancel).Methods("POST")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/imports/"+testCase.inputId+"/cancel", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestImportJobHandler_GetErrors(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIImportJobService, jobId string)
+	testTable := []struct {
+		name                 string
+		inputId              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name:    "row errors are downloaded as csv",
+			inputId: "3d624904890861643c610064",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, jobId string) {
+				s.EXPECT().Get(jobId).Return(&domain.ImportJob{
+					Id:     jobId,
+					Status: domain.ImportJobStatusSucceeded,
+					Errors: testRowErrors,
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "text/csv",
+			expectedResponseBody: "line,column,value,code,message\n" +
+				"5,salary,1000,invalid_salary,The salary must be an amount followed by a currency\n" +
+				"7,,,invalid_row,\"bare \"\" in non-quoted-field\"\n",
+		},
+		{
+			name:    "get not found when the job does not exist",
+			inputId: "3d624904890861643c610064",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, jobId string) {
+				s.EXPECT().Get(jobId).Return(nil, domain.ErrImportJobNotFound)
+			},
+			expectedStatusCode:  404,
+			expectedContentType: "application/json",
+			expectedResponseBody: `{"Errors":["Import job is not found"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockIImportJobService(c)
+			testCase.mockBehavior(service, testCase.inputId)
+
+			handler := ImportJobHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/imports/{id:[a-zA-Z0-9]*}/errors", handler.GetErrors).Methods("GET")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/imports/"+testCase.inputId+"/errors", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, testCase.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
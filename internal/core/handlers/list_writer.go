@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// table lays out the items of a list as the rows of CSV and XLSX responses
+type table struct {
+	columns []string
+	// row returns the cells of the item in the order of columns
+	row func(item interface{}) []interface{}
+}
+
+// listWriter writes the items of a list response one at a time
+type listWriter interface {
+	Write(item interface{}) error
+	// Close writes the end of the response, the items are not complete without it
+	Close() error
+}
+
+func newListWriter(list *listResponse, w io.Writer) (listWriter, error) {
+	switch list.mediaType {
+	case mediaTypeCSV:
+		return newCSVListWriter(w, list.table)
+	case mediaTypeXLSX:
+		return newXLSXListWriter(w, list.table)
+	case mediaTypeNDJSON:
+		return &ndjsonListWriter{w}, nil
+	}
+	return newJSONListWriter(w, list.jsonFields, list.itemsField)
+}
+
+// optionalCell is the cell of a number that may be missing, a missing number is an empty cell
+func optionalCell(value *float64) interface{} {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// cellText formats numbers without exponents and trailing zeros
+func cellText(value interface{}) string {
+	if number, ok := value.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+type csvListWriter struct {
+	writer *csv.Writer
+	table  *table
+}
+
+func newCSVListWriter(w io.Writer, layout *table) (listWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(layout.columns); err != nil {
+		return nil, err
+	}
+	return &csvListWriter{writer, layout}, nil
+}
+
+func (cw *csvListWriter) Write(item interface{}) error {
+	values := cw.table.row(item)
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = cellText(value)
+	}
+	return cw.writer.Write(record)
+}
+
+func (cw *csvListWriter) Close() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// jsonListWriter writes the items as a JSON array, or as the array field of an object with the fields of a value
+type jsonListWriter struct {
+	w      io.Writer
+	suffix string
+	items  int
+}
+
+func newJSONListWriter(w io.Writer, fields interface{}, itemsField string) (listWriter, error) {
+	prefix, suffix := []byte("["), "]\n"
+	if fields != nil {
+		object, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		key, err := json.Marshal(itemsField)
+		if err != nil {
+			return nil, err
+		}
+		prefix = object[:len(object)-1]
+		if len(object) > 2 {
+			prefix = append(prefix, ',')
+		}
+		prefix = append(append(prefix, key...), ":["...)
+		suffix = "]}\n"
+	}
+
+	if _, err := w.Write(prefix); err != nil {
+		return nil, err
+	}
+	return &jsonListWriter{w: w, suffix: suffix}, nil
+}
+
+func (jw *jsonListWriter) Write(item interface{}) error {
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	if jw.items > 0 {
+		encoded = append([]byte(","), encoded...)
+	}
+	jw.items++
+	_, err = jw.w.Write(encoded)
+	return err
+}
+
+func (jw *jsonListWriter) Close() error {
+	_, err := io.WriteString(jw.w, jw.suffix)
+	return err
+}
+
+// ndjsonListWriter writes every item as a JSON value on its own line
+type ndjsonListWriter struct {
+	w io.Writer
+}
+
+func (nw *ndjsonListWriter) Write(item interface{}) error {
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	_, err = nw.w.Write(append(encoded, '\n'))
+	return err
+}
+
+func (nw *ndjsonListWriter) Close() error {
+	return nil
+}
+
+// xlsxListWriter writes a workbook with one sheet, the rows are written to the sheet entry of the zip as they come
+type xlsxListWriter struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	table   *table
+}
+
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+func newXLSXListWriter(w io.Writer, layout *table) (listWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+
+	xw := &xlsxListWriter{archive, sheet, layout}
+	header := make([]interface{}, len(layout.columns))
+	for i, column := range layout.columns {
+		header[i] = column
+	}
+	if err := xw.writeRow(header); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxListWriter) Write(item interface{}) error {
+	return xw.writeRow(xw.table.row(item))
+}
+
+// writeRow writes numbers as number cells and everything else as inline string cells
+func (xw *xlsxListWriter) writeRow(values []interface{}) error {
+	if _, err := io.WriteString(xw.sheet, "<row>"); err != nil {
+		return err
+	}
+	for _, value := range values {
+		var err error
+		switch value.(type) {
+		case float64, int:
+			_, err = io.WriteString(xw.sheet, "<c><v>"+cellText(value)+"</v></c>")
+		default:
+			if _, err = io.WriteString(xw.sheet, `<c t="inlineStr"><is><t>`); err == nil {
+				if err = xml.EscapeText(xw.sheet, []byte(cellText(value))); err == nil {
+					_, err = io.WriteString(xw.sheet, "</t></is></c>")
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(xw.sheet, "</row>")
+	return err
+}
+
+func (xw *xlsxListWriter) Close() error {
+	if _, err := io.WriteString(xw.sheet, "</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	return xw.archive.Close()
+}
@@ -27,18 +27,13 @@ func (mw MiddlewareHandler) LogURL(next http.Handler) http.Handler {
 func (mw MiddlewareHandler) CheckJWT(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		mw.logger.Info("Start jwtMiddleware")
-		tokenString := r.Header.Get("jwt")
 
-		claims := jwt.MapClaims{}
-
-		_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-		})
+		claims, err := parseClaims(r)
 		if err != nil {
 			mw.logger.Error("Error parsing jwt: ", err)
 		}
 
-		if claims["isAdmin"] == true {
+		if err == nil && claims["isAdmin"] == true {
 			mw.logger.Info("Authenticated user")
 			next.ServeHTTP(w, r)
 		} else {
@@ -49,3 +44,27 @@ func (mw MiddlewareHandler) CheckJWT(next http.Handler) http.Handler {
 
 	})
 }
+
+func parseClaims(r *http.Request) (jwt.MapClaims, error) {
+	tokenString := r.Header.Get("jwt")
+
+	claims := jwt.MapClaims{}
+
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// requestUsername returns the name of the user from a valid jwt of the request or an empty string
+func requestUsername(r *http.Request) string {
+	claims, err := parseClaims(r)
+	if err != nil {
+		return ""
+	}
+	username, _ := claims["username"].(string)
+	return username
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/sirupsen/logrus"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	mediaTypeJSON   = "application/json"
+	mediaTypeNDJSON = "application/x-ndjson"
+	mediaTypeCSV    = "text/csv"
+	mediaTypeXLSX   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// listMediaTypes are the media types of list endpoints, JSON is the default
+var listMediaTypes = []string{mediaTypeJSON, mediaTypeCSV, mediaTypeNDJSON}
+
+// formatMediaTypes are the file formats that can be chosen with the format query parameter instead of Accept
+var formatMediaTypes = map[string]string{
+	"json":   mediaTypeJSON,
+	"ndjson": mediaTypeNDJSON,
+	"csv":    mediaTypeCSV,
+	"xlsx":   mediaTypeXLSX,
+}
+
+// negotiate returns the offered media type with the highest quality in the Accept header of the request,
+// the first offered type wins ties and is used when the request has no Accept header
+func negotiate(r *http.Request, offered []string) (string, bool) {
+	accept := strings.TrimSpace(r.Header.Get("Accept"))
+	if len(accept) == 0 {
+		return offered[0], true
+	}
+
+	best, bestQuality := "", 0.0
+	for _, mediaType := range offered {
+		if quality := acceptQuality(accept, mediaType); quality > bestQuality {
+			best, bestQuality = mediaType, quality
+		}
+	}
+	return best, bestQuality > 0
+}
+
+// acceptQuality returns the quality of the most specific range of the Accept header matching the media type
+func acceptQuality(accept string, mediaType string) float64 {
+	quality, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		rangeSpecificity := matchSpecificity(mediaRange, mediaType)
+		if rangeSpecificity <= specificity {
+			continue
+		}
+		rangeQuality := 1.0
+		if value, ok := params["q"]; ok {
+			rangeQuality, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+		}
+		quality, specificity = rangeQuality, rangeSpecificity
+	}
+	return quality
+}
+
+// matchSpecificity is 2 when the range is the media type, 1 for its type/* and 0 for */*, -1 when the range does not match
+func matchSpecificity(mediaRange string, mediaType string) int {
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	}
+	return -1
+}
+
+func notAcceptable(w http.ResponseWriter, offered []string, logger *logrus.Logger) {
+	HandleErrorWithStatus(w, http.StatusNotAcceptable, "The response can only be one of: "+strings.Join(offered, ", "), logger)
+}
+
+// renderJSON responds with the value as JSON when the request accepts it and with 406 otherwise
+func renderJSON(w http.ResponseWriter, r *http.Request, status int, value interface{}, logger *logrus.Logger) {
+	if _, ok := negotiate(r, []string{mediaTypeJSON}); !ok {
+		notAcceptable(w, []string{mediaTypeJSON}, logger)
+		return
+	}
+	writeJSON(w, status, value, logger)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}, logger *logrus.Logger) {
+	w.Header().Set("Content-Type", mediaTypeJSON)
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(value)
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
+// listResponse streams the items of a list in the media type negotiated with the request
+type listResponse struct {
+	mediaType string
+	table     *table
+	// fileName makes the response an attachment, the extension of the media type is added to it
+	fileName string
+	// jsonFields are the fields of the JSON object holding the items in itemsField, JSON lists are plain arrays without them
+	jsonFields interface{}
+	itemsField string
+}
+
+// negotiateList chooses the media type of the list from the offered ones, it responds with 406 and returns false
+// when none is acceptable. The format query parameter names the media type instead of Accept when it is set.
+func negotiateList(w http.ResponseWriter, r *http.Request, offered []string, layout *table, logger *logrus.Logger) (*listResponse, bool) {
+	if format := r.URL.Query().Get("format"); len(format) > 0 {
+		mediaType, ok := formatMediaTypes[format]
+		if !ok || !containsMediaType(offered, mediaType) {
+			HandleServiceError(w, domain.NewValidationError("Format %q is not supported, use one of: %s",
+				format, strings.Join(formatNames(offered), ", ")), logger)
+			return nil, false
+		}
+		return &listResponse{mediaType: mediaType, table: layout}, true
+	}
+
+	mediaType, ok := negotiate(r, offered)
+	if !ok {
+		notAcceptable(w, offered, logger)
+		return nil, false
+	}
+	return &listResponse{mediaType: mediaType, table: layout}, true
+}
+
+// render writes the items passed to write by stream. The body is started with the first item
+// so an error returned by stream before it is still answered with an error status.
+func (lr *listResponse) render(w http.ResponseWriter, stream func(write func(item interface{}) error) error, logger *logrus.Logger) {
+	var writer listWriter
+	start := func() error {
+		w.Header().Set("Content-Type", lr.mediaType)
+		if len(lr.fileName) > 0 {
+			w.Header().Set("Content-Disposition", `attachment; filename="`+lr.fileName+"."+formatNames([]string{lr.mediaType})[0]+`"`)
+		}
+		started, err := newListWriter(lr, w)
+		if err != nil {
+			return err
+		}
+		writer = started
+		return nil
+	}
+
+	err := stream(func(item interface{}) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return writer.Write(item)
+	})
+	if err != nil {
+		logger.Error(err)
+		if writer == nil {
+			HandleServiceError(w, err, logger)
+		}
+		return
+	}
+
+	if writer == nil {
+		if err := start(); err != nil {
+			logger.Error(err)
+			return
+		}
+	}
+	if err := writer.Close(); err != nil {
+		logger.Error(err)
+	}
+}
+
+func containsMediaType(mediaTypes []string, mediaType string) bool {
+	for _, offered := range mediaTypes {
+		if offered == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// formatNames returns the format query parameter values of the media types
+func formatNames(mediaTypes []string) []string {
+	var names []string
+	for _, mediaType := range mediaTypes {
+		for name, formatMediaType := range formatMediaTypes {
+			if formatMediaType == mediaType {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	testTable := []struct {
+		name              string
+		accept            string
+		offered           []string
+		expectedMediaType string
+		expectedOk        bool
+	}{
+		{
+			name:              "first offered type is used without Accept",
+			offered:           listMediaTypes,
+			expectedMediaType: mediaTypeJSON,
+			expectedOk:        true,
+		},
+		{
+			name:              "first offered type is used for any type",
+			accept:            "*/*",
+			offered:           exportMediaTypes,
+			expectedMediaType: mediaTypeCSV,
+			expectedOk:        true,
+		},
+		{
+			name:              "exact type is chosen",
+			accept:            "text/csv",
+			offered:           listMediaTypes,
+			expectedMediaType: mediaTypeCSV,
+			expectedOk:        true,
+		},
+		{
+			name:              "type with the highest quality is chosen",
+			accept:            "application/json;q=0.4, text/csv;q=0.9, */*;q=0.1",
+			offered:           listMediaTypes,
+			expectedMediaType: mediaTypeCSV,
+			expectedOk:        true,
+		},
+		{
+			name:              "subtype wildcard matches",
+			accept:            "text/html, text/*;q=0.8",
+			offered:           listMediaTypes,
+			expectedMediaType: mediaTypeCSV,
+			expectedOk:        true,
+		},
+		{
+			name:              "more specific range overrides the wildcard",
+			accept:            "*/*, application/json;q=0",
+			offered:           listMediaTypes,
+			expectedMediaType: mediaTypeCSV,
+			expectedOk:        true,
+		},
+		{
+			name:       "nothing is chosen when no offered type is accepted",
+			accept:     "application/xml, text/html",
+			offered:    listMediaTypes,
+			expectedOk: false,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/users", nil)
+			if len(testCase.accept) > 0 {
+				req.Header.Set("Accept", testCase.accept)
+			}
+
+			mediaType, ok := negotiate(req, testCase.offered)
+
+			assert.Equal(t, testCase.expectedOk, ok)
+			assert.Equal(t, testCase.expectedMediaType, mediaType)
+		})
+	}
+}
@@ -1,45 +1,69 @@
 package handlers
 
 import (
-	"bytes"
-	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
 	"github.com/inkoba/app_for_HR/internal/core/ports"
 	"github.com/sirupsen/logrus"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 )
 
 type SalaryHandler struct {
-	logger        *logrus.Logger
-	salaryService ports.ISalaryService
+	logger           *logrus.Logger
+	salaryService    ports.ISalaryService
+	importJobService ports.IImportJobService
 }
 
 var _ ports.ISalaryHandler = (*SalaryHandler)(nil)
 
-func NewSalaryHandler(salaryService ports.ISalaryService, logger *logrus.Logger) ports.ISalaryHandler {
+func NewSalaryHandler(salaryService ports.ISalaryService, importJobService ports.IImportJobService, logger *logrus.Logger) ports.ISalaryHandler {
 	return &SalaryHandler{
 		logger,
 		salaryService,
+		importJobService,
 	}
 }
 
+// UploadFile starts a background import of the "file" part of the multipart form and responds with the import job.
+// The optional mode query parameter selects replace, append or upsert import.
+// With dryRun=true the file is only validated and the upload report is returned right away.
+// The optional collectedAt query parameter, like 2021-06-30, selects the exchange rates of the survey collection date.
 func (sh SalaryHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
-	err := r.ParseMultipartForm(10 << 20)
+	dryRun := false
+	if value := r.URL.Query().Get("dryRun"); len(value) > 0 {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			HandleServiceError(w, domain.NewValidationError("dryRun must be true or false"), sh.logger)
+			return
+		}
+		dryRun = parsed
+	}
+
+	collectedAt, err := queryDate(r, "collectedAt")
+	if err != nil {
+		HandleServiceError(w, err, sh.logger)
+		return
+	}
+
+	reader, err := r.MultipartReader()
 	if err != nil {
 		sh.logger.Error(err)
 		HandleError(w, err.Error(), sh.logger)
 		return
 	}
 
-	file, _, err := r.FormFile("file")
+	file, err := nextFilePart(reader)
 	if err != nil {
 		sh.logger.Error(err)
 		HandleError(w, err.Error(), sh.logger)
 		return
 	}
 
-	defer func(file multipart.File) {
+	defer func(file *multipart.Part) {
 		err := file.Close()
 		if err != nil {
 			sh.logger.Error(err)
@@ -47,24 +71,103 @@ func (sh SalaryHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 		}
 	}(file)
 
-	buf := bytes.NewBuffer(nil)
-	if _, err := io.Copy(buf, file); err != nil {
+	upload := &request.SalaryUpload{
+		File:     file,
+		FileName: file.FileName(),
+		Uploader: requestUsername(r),
+		Mode:     r.URL.Query().Get("mode"),
+		DryRun:   dryRun,
+	}
+	if collectedAt != nil {
+		upload.CollectedAt = *collectedAt
+	}
+	if dryRun {
+		report, err := sh.salaryService.Create(r.Context(), upload)
+		if err != nil {
+			sh.logger.Error(err)
+			HandleServiceError(w, err, sh.logger)
+			return
+		}
+		renderJSON(w, r, http.StatusOK, report, sh.logger)
+		return
+	}
+
+	job, err := sh.importJobService.Start(upload)
+	if err != nil {
 		sh.logger.Error(err)
-		HandleError(w, err.Error(), sh.logger)
+		HandleServiceError(w, err, sh.logger)
+		return
+	}
+
+	w.Header().Set("Location", "/api/imports/"+job.Id)
+	renderJSON(w, r, http.StatusAccepted, job, sh.logger)
+}
+
+func nextFilePart(reader *multipart.Reader) (*multipart.Part, error) {
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, http.ErrMissingFile
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+		if err := part.Close(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Rollback makes the previous salary dataset live again
+func (sh SalaryHandler) Rollback(w http.ResponseWriter, r *http.Request) {
+	dataset, err := sh.salaryService.Rollback()
+	if err != nil {
+		sh.logger.Error(err)
+		HandleServiceError(w, err, sh.logger)
 		return
 	}
 
-	report, err := sh.salaryService.Create(buf.Bytes())
+	renderJSON(w, r, http.StatusOK, dataset, sh.logger)
+}
+
+func (sh SalaryHandler) GetAllVersions(w http.ResponseWriter, r *http.Request) {
+	datasets, err := sh.salaryService.GetAllVersions()
 	if err != nil {
 		sh.logger.Error(err)
-		HandleError(w, err.Error(), sh.logger)
+		HandleServiceError(w, err, sh.logger)
+		return
+	}
+
+	renderJSON(w, r, http.StatusOK, datasets, sh.logger)
+}
+
+func (sh SalaryHandler) ActivateVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	dataset, err := sh.salaryService.ActivateVersion(id)
+	if err != nil {
+		sh.logger.Error(err)
+		HandleServiceError(w, err, sh.logger)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(&report)
+	renderJSON(w, r, http.StatusOK, dataset, sh.logger)
+}
+
+func (sh SalaryHandler) DeleteVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	err := sh.salaryService.DeleteVersion(id)
 	if err != nil {
 		sh.logger.Error(err)
-		HandleError(w, err.Error(), sh.logger)
+		HandleServiceError(w, err, sh.logger)
+		return
 	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	mock_ports "github.com/inkoba/app_for_HR/internal/core/ports/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testDatasetCreatedAt = time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+const testUploadFile = "Salary,Level of seniority,Years total,Country,Level of English\n1000 USD,Junior,1,Belarus,B1\n"
+
+func TestSalaryHandler_UploadFile(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockIImportJobService, ss *mock_ports.MockISalaryService)
+	testTable := []struct {
+		name                 string
+		formField            string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedLocation     string
+		expectedResponseBody string
+	}{
+		{
+			name:      "import job is accepted when the file is uploaded",
+			formField: "file",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, ss *mock_ports.MockISalaryService) {
+				s.EXPECT().Start(gomock.Any()).DoAndReturn(func(upload *request.SalaryUpload) (*domain.ImportJob, error) {
+					content, err := io.ReadAll(upload.File)
+					assert.NoError(t, err)
+					assert.Equal(t, testUploadFile, string(content))
+					assert.Equal(t, "salaries.csv", upload.FileName)
+					assert.Equal(t, "", upload.Mode)
+					return &domain.ImportJob{
+						Id:        "3d624904890861643c610064",
+						Status:    domain.ImportJobStatusQueued,
+						FileName:  upload.FileName,
+						Mode:      request.UploadModeReplace,
+						CreatedAt: testDatasetCreatedAt,
+					}, nil
+				})
+			},
+			expectedStatusCode: 202,
+			expectedLocation:   "/api/imports/3d624904890861643c610064",
+			expectedResponseBody: `{"id":"3d624904890861643c610064","status":"queued","fileName":"salaries.csv","mode":"replace","rowsProcessed":0,"rowsSkipped":0,"errors":null,"createdAt":"2022-05-01T10:00:00Z"}
+`,
+		},
+		{
+			name:      "upload mode is taken from the query",
+			formField: "file",
+			query:     "?mode=append",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, ss *mock_ports.MockISalaryService) {
+				s.EXPECT().Start(gomock.Any()).DoAndReturn(func(upload *request.SalaryUpload) (*domain.ImportJob, error) {
+					assert.Equal(t, request.UploadModeAppend, upload.Mode)
+					return &domain.ImportJob{
+						Id:        "3d624904890861643c610064",
+						Status:    domain.ImportJobStatusQueued,
+						Mode:      upload.Mode,
+						CreatedAt: testDatasetCreatedAt,
+					}, nil
+				})
+			},
+			expectedStatusCode: 202,
+			expectedLocation:   "/api/imports/3d624904890861643c610064",
+			expectedResponseBody: `{"id":"3d624904890861643c610064","status":"queued","mode":"append","rowsProcessed":0,"rowsSkipped":0,"errors":null,"createdAt":"2022-05-01T10:00:00Z"}
+`,
+		},
+		{
+			name:      "get bad request when the upload mode is not supported",
+			formField: "file",
+			query:     "?mode=merge",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, ss *mock_ports.MockISalaryService) {
+				s.EXPECT().Start(gomock.Any()).Return(nil, domain.NewValidationError("Upload mode %q is not supported", "merge"))
+			},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["Upload mode \"merge\" is not supported"]}
+`,
+		},
+		{
+			name:      "dry run report is returned without starting an import job",
+			formField: "file",
+			query:     "?dryRun=true",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, ss *mock_ports.MockISalaryService) {
+				ss.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, upload *request.SalaryUpload) (*response.SalaryUploadReport, error) {
+					assert.True(t, upload.DryRun)
+					return &response.SalaryUploadReport{
+						TotalRecords: 1,
+						Samples: []*response.SalarySample{{
+							Amount: 1000, AmountMin: 1000, AmountMax: 1000, Currency: "USD", AmountUSD: 1000, LevelOfSeniority: "Junior", SeniorityRank: 1, YearsTotal: 1, Country: "Belarus", LevelOfEnglish: "B1",
+						}},
+					}, nil
+				})
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"TotalRecords":1,"SkippedRecords":0,"Errors":null,"Samples":[{"Amount":1000,"AmountMin":1000,"AmountMax":1000,"Currency":"USD","AmountUSD":1000,"LevelOfSeniority":"Junior","SeniorityRank":1,"YearsTotal":1,"Country":"Belarus","LevelOfEnglish":"B1"}]}
+`,
+		},
+		{
+			name:      "collection date is taken from the query",
+			formField: "file",
+			query:     "?collectedAt=2021-06-30",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, ss *mock_ports.MockISalaryService) {
+				s.EXPECT().Start(gomock.Any()).DoAndReturn(func(upload *request.SalaryUpload) (*domain.ImportJob, error) {
+					assert.Equal(t, time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC), upload.CollectedAt)
+					return &domain.ImportJob{
+						Id:        "3d624904890861643c610064",
+						Status:    domain.ImportJobStatusQueued,
+						CreatedAt: testDatasetCreatedAt,
+					}, nil
+				})
+			},
+			expectedStatusCode: 202,
+			expectedLocation:   "/api/imports/3d624904890861643c610064",
+			expectedResponseBody: `{"id":"3d624904890861643c610064","status":"queued","rowsProcessed":0,"rowsSkipped":0,"errors":null,"createdAt":"2022-05-01T10:00:00Z"}
+`,
+		},
+		{
+			name:               "get bad request when the collection date is not a date",
+			formField:          "file",
+			query:              "?collectedAt=June",
+			mockBehavior:       func(s *mock_ports.MockIImportJobService, ss *mock_ports.MockISalaryService) {},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["collectedAt must be a date like 2021-06-30"]}
+`,
+		},
+		{
+			name:               "get bad request when the dry run flag is not a boolean",
+			formField:          "file",
+			query:              "?dryRun=maybe",
+			mockBehavior:       func(s *mock_ports.MockIImportJobService, ss *mock_ports.MockISalaryService) {},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{"Errors":["dryRun must be true or false"]}
+`,
+		},
+		{
+			name:               "get error when the form has no file field",
+			formField:          "document",
+			mockBehavior:       func(s *mock_ports.MockIImportJobService, ss *mock_ports.MockISalaryService) {},
+			expectedStatusCode: 500,
+			expectedResponseBody: `{"Errors":["http: no such file"]}
+`,
+		},
+		{
+			name:      "get error when the file can not be saved",
+			formField: "file",
+			mockBehavior: func(s *mock_ports.MockIImportJobService, ss *mock_ports.MockISalaryService) {
+				s.EXPECT().Start(gomock.Any()).Return(nil, errors.New("no space left on device"))
+			},
+			expectedStatusCode: 500,
+			expectedResponseBody: `{"Errors":["no space left on device"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			jobService := mock_ports.NewMockIImportJobService(c)
+			service := mock_ports.NewMockISalaryService(c)
+			testCase.mockBehavior(jobService, service)
+
+			handler := SalaryHandler{logrus.New(), service, jobService}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/salaries", handler.UploadFile).Methods("POST")
+
+			// Create Request
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile(testCase.formField, "salaries.csv")
+			assert.NoError(t, err)
+			_, err = part.Write([]byte(testUploadFile))
+			assert.NoError(t, err)
+			assert.NoError(t, writer.Close())
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/salaries"+testCase.query, body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, testCase.expectedLocation, w.Header().Get("Location"))
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestSalaryHandler_Rollback(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryService)
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "previous dataset is returned after the rollback",
+			mockBehavior: func(s *mock_ports.MockISalaryService) {
+				s.EXPECT().Rollback().Return(&domain.SalaryDataset{
+					Id:        id,
+					Status:    domain.DatasetStatusReady,
+					CreatedAt: testDatasetCreatedAt,
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"id":"3d624904890861643c610064","status":"ready","createdAt":"2022-05-01T10:00:00Z","active":false}
+`,
+		},
+		{
+			name: "get not found when there is no previous dataset",
+			mockBehavior: func(s *mock_ports.MockISalaryService) {
+				s.EXPECT().Rollback().Return(nil, domain.ErrDatasetNotFound)
+			},
+			expectedStatusCode: 404,
+			expectedResponseBody: `{"Errors":["Salary dataset is not found"]}
+`,
+		},
+		{
+			name: "get error when the database is unavailable",
+			mockBehavior: func(s *mock_ports.MockISalaryService) {
+				s.EXPECT().Rollback().Return(nil, errors.New("database is unavailable"))
+			},
+			expectedStatusCode: 500,
+			expectedResponseBody: `{"Errors":["database is unavailable"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockISalaryService(c)
+			testCase.mockBehavior(service)
+
+			handler := SalaryHandler{logrus.New(), service, nil}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/salaries/rollback", handler.Rollback).Methods("POST")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/salaries/rollback", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestSalaryHandler_GetAllVersions(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryService)
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "get all versions when the database is available",
+			mockBehavior: func(s *mock_ports.MockISalaryService) {
+				s.EXPECT().GetAllVersions().Return([]*domain.SalaryDataset{{
+					Id:          id,
+					Status:      domain.DatasetStatusReady,
+					FileName:    "salaries.csv",
+					Uploader:    "admin",
+					CreatedAt:   testDatasetCreatedAt,
+					ActivatedAt: &testDatasetCreatedAt,
+					Active:      true,
+				}}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `[{"id":"3d624904890861643c610064","status":"ready","fileName":"salaries.csv","uploader":"admin","createdAt":"2022-05-01T10:00:00Z","activatedAt":"2022-05-01T10:00:00Z","active":true}]
+`,
+		},
+		{
+			name: "get error when the database is unavailable",
+			mockBehavior: func(s *mock_ports.MockISalaryService) {
+				s.EXPECT().GetAllVersions().Return(nil, errors.New("database is unavailable"))
+			},
+			expectedStatusCode: 500,
+			expectedResponseBody: `{"Errors":["database is unavailable"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockISalaryService(c)
+			testCase.mockBehavior(service)
+
+			handler := SalaryHandler{logrus.New(), service, nil}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/salaries/versions", handler.GetAllVersions).Methods("GET")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/salaries/versions", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestSalaryHandler_ActivateVersion(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryService, versionId string)
+	testTable := []struct {
+		name                 string
+		inputId              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "version is activated",
+			inputId: "3d624904890861643c610064",
+			mockBehavior: func(s *mock_ports.MockISalaryService, versionId string) {
+				s.EXPECT().ActivateVersion(versionId).Return(&domain.SalaryDataset{
+					Id:        id,
+					Status:    domain.DatasetStatusReady,
+					CreatedAt: testDatasetCreatedAt,
+					Active:    true,
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"id":"3d624904890861643c610064","status":"ready","createdAt":"2022-05-01T10:00:00Z","active":true}
+`,
+		},
+		{
+			name:    "get not found when the version does not exist",
+			inputId: "3d624904890861643c610064",
+			mockBehavior: func(s *mock_ports.MockISalaryService, versionId string) {
+				s.EXPECT().ActivateVersion(versionId).Return(nil, domain.ErrDatasetNotFound)
+			},
+			expectedStatusCode: 404,
+			expectedResponseBody: `{"Errors":["Salary dataset is not found"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockISalaryService(c)
+			testCase.mockBehavior(service, testCase.inputId)
+
+			handler := SalaryHandler{logrus.New(), service, nil}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/salaries/versions/{id:[a-zA-Z0-9]*}/activate", handler.ActivateVersion).Methods("POST")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/api/salaries/versions/"+testCase.inputId+"/activate", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
+
+func TestSalaryHandler_DeleteVersion(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryService, id string)
+	testTable := []struct {
+		name                 string
+		inputId              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "version is deleted",
+			inputId: "3d624904890861643c610064",
+			mockBehavior: func(s *mock_ports.MockISalaryService, id string) {
+				s.EXPECT().DeleteVersion(id).Return(nil)
+			},
+			expectedStatusCode: 204,
+		},
+		{
+			name:    "get conflict when the version is active",
+			inputId: "3d624904890861643c610064",
+			mockBehavior: func(s *mock_ports.MockISalaryService, id string) {
+				s.EXPECT().DeleteVersion(id).Return(domain.ErrDatasetActive)
+			},
+			expectedStatusCode: 409,
+			expectedResponseBody: `{"Errors":["Salary dataset is active and can not be deleted"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockISalaryService(c)
+			testCase.mockBehavior(service, testCase.inputId)
+
+			handler := SalaryHandler{logrus.New(), service, nil}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/salaries/versions/{id:[a-zA-Z0-9]*}", handler.DeleteVersion).Methods("DELETE")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/api/salaries/versions/"+testCase.inputId, nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type TaxonomyHandler struct {
+	seniorityService ports.ISeniorityTaxonomyService
+	logger           *logrus.Logger
+}
+
+var _ ports.ITaxonomyHandler = (*TaxonomyHandler)(nil)
+
+func NewTaxonomyHandler(seniorityService ports.ISeniorityTaxonomyService, logger *logrus.Logger) *TaxonomyHandler {
+	return &TaxonomyHandler{
+		seniorityService,
+		logger,
+	}
+}
+
+// Seniority responds with the canonical levels of seniority ordered by rank and their aliases
+func (th TaxonomyHandler) Seniority(w http.ResponseWriter, r *http.Request) {
+	renderJSON(w, r, http.StatusOK, th.seniorityService.GetSeniority(), th.logger)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	mock_ports "github.com/inkoba/app_for_HR/internal/core/ports/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTaxonomyHandler_Seniority(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISeniorityTaxonomyService)
+
+	taxonomy, err := domain.NewSeniorityTaxonomy([]*domain.SeniorityLevel{
+		{Name: "Junior", Aliases: []string{"Jr"}},
+		{Name: "Senior", Aliases: []string{"Sr", "Senior developer"}},
+	})
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		name                 string
+		accept               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "levels of seniority are returned ordered by rank",
+			mockBehavior: func(s *mock_ports.MockISeniorityTaxonomyService) {
+				s.EXPECT().GetSeniority().Return(taxonomy)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"levels":[{"name":"Junior","rank":1,"aliases":["Jr"]},{"name":"Senior","rank":2,"aliases":["Sr","Senior developer"]}]}
+`,
+		},
+		{
+			name:   "get not acceptable when JSON is not accepted",
+			accept: "text/csv",
+			mockBehavior: func(s *mock_ports.MockISeniorityTaxonomyService) {
+				s.EXPECT().GetSeniority().Return(taxonomy)
+			},
+			expectedStatusCode: 406,
+			expectedResponseBody: `{"Errors":["The response can only be one of: application/json"]}
+`,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			service := mock_ports.NewMockISeniorityTaxonomyService(c)
+			testCase.mockBehavior(service)
+
+			handler := TaxonomyHandler{service, logrus.New()}
+
+			// Init Endpoint
+			r := mux.NewRouter()
+			r.HandleFunc("/api/taxonomy/seniority", handler.Seniority).Methods("GET")
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/taxonomy/seniority", nil)
+			if len(testCase.accept) > 0 {
+				req.Header.Set("Accept", testCase.accept)
+			}
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
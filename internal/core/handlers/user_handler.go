@@ -15,6 +15,14 @@ type UserHandler struct {
 	logger      *logrus.Logger
 }
 
+var usersTable = &table{
+	columns: []string{"id", "username", "isAdmin"},
+	row: func(item interface{}) []interface{} {
+		user := item.(*domain.User)
+		return []interface{}{user.Id.Hex(), user.Username, user.IsAdmin}
+	},
+}
+
 func NewUserHandler(service ports.IUserService, logger *logrus.Logger) ports.IUserHandler {
 	return UserHandler{
 		service,
@@ -22,19 +30,28 @@ func NewUserHandler(service ports.IUserService, logger *logrus.Logger) ports.IUs
 	}
 }
 
-func (ah UserHandler) GetAll(w http.ResponseWriter, _ *http.Request) {
-	users, err := ah.userService.GetAll()
-	if err != nil {
-		ah.logger.Error(err)
-		HandleError(w, err.Error(), ah.logger)
+// GetAll responds with the users as JSON, CSV or NDJSON, the CSV has no passwords
+func (ah UserHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	list, ok := negotiateList(w, r, listMediaTypes, usersTable, ah.logger)
+	if !ok {
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(users)
+	users, err := ah.userService.GetAll()
 	if err != nil {
 		ah.logger.Error(err)
 		HandleError(w, err.Error(), ah.logger)
+		return
 	}
+
+	list.render(w, func(write func(item interface{}) error) error {
+		for _, user := range users {
+			if err := write(user); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, ah.logger)
 }
 
 func (ah UserHandler) Get(w http.ResponseWriter, r *http.Request) {
@@ -45,14 +62,10 @@ func (ah UserHandler) Get(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		ah.logger.Error(err)
 		HandleError(w, err.Error(), ah.logger)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(user)
-	if err != nil {
-		ah.logger.Error(err)
-		HandleError(w, err.Error(), ah.logger)
-	}
+	renderJSON(w, r, http.StatusOK, user, ah.logger)
 }
 
 func (ah UserHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -76,14 +89,10 @@ func (ah UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		ah.logger.Error(err)
 		HandleError(w, err.Error(), ah.logger)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(id)
-	if err != nil {
-		ah.logger.Error(err)
-		HandleError(w, err.Error(), ah.logger)
-	}
+	renderJSON(w, r, http.StatusOK, id, ah.logger)
 }
 func (ah UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
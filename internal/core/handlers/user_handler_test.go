@@ -17,10 +17,27 @@ var id = [12]byte{61, 'b', 73, 4, 137, 8, 'a', 'd', 60, 'a', 0, 'd'}
 
 func TestUserHandler_GetAll(t *testing.T) {
 	type mockBehavior func(s *mock_ports.MockIUserService)
+	users := []*domain.User{
+		{
+			Id:       id,
+			Username: "admin",
+			Password: "$2a$12$QSEvrvXWWegdupNz73bYeedLkOl5VRUNWT8iG2hGeeN5Z1FjlfBxq",
+			IsAdmin:  true,
+		},
+		{
+			Id:       id,
+			Username: "user",
+			Password: "$2a$12$QSEvrvXWWegdupNz73bYeedLkOl5VRUNWT8iG2hGeeN5Z1FjlfBxq",
+			IsAdmin:  false,
+		},
+	}
+
 	testTable := []struct {
 		name                 string
+		accept               string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
+		expectedContentType  string
 		expectedResponseBody string
 	}{
 		{
@@ -41,7 +58,8 @@ func TestUserHandler_GetAll(t *testing.T) {
 					},
 				}, nil)
 			},
-			expectedStatusCode: 200,
+			expectedStatusCode:  200,
+			expectedContentType: "application/json",
 			expectedResponseBody: `[{"id":"3d624904890861643c610064","username":"admin","password":"$2a$12$QSEvrvXWWegdupNz73bYeedLkOl5VRUNWT8iG2hGeeN5Z1FjlfBxq","isAdmin":true},{"id":"3d624904890861643c610064","username":"user","password":"$2a$12$QSEvrvXWWegdupNz73bYeedLkOl5VRUNWT8iG2hGeeN5Z1FjlfBxq","isAdmin":false}]
 `},
 		{
@@ -49,9 +67,39 @@ func TestUserHandler_GetAll(t *testing.T) {
 			mockBehavior: func(s *mock_ports.MockIUserService) {
 				s.EXPECT().GetAll().Return(nil, errors.New("database is unavailable"))
 			},
-			expectedStatusCode: 500,
+			expectedStatusCode:  500,
+			expectedContentType: "application/json",
 			expectedResponseBody: `{"Errors":["database is unavailable"]}
-null
+`},
+		{
+			name:   "get users as CSV without passwords",
+			accept: "text/csv",
+			mockBehavior: func(s *mock_ports.MockIUserService) {
+				s.EXPECT().GetAll().Return(users, nil)
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "text/csv",
+			expectedResponseBody: `id,username,isAdmin
+3d624904890861643c610064,admin,true
+3d624904890861643c610064,user,false
+`},
+		{
+			name:   "get users as NDJSON when it is preferred",
+			accept: "application/json;q=0.5, application/x-ndjson",
+			mockBehavior: func(s *mock_ports.MockIUserService) {
+				s.EXPECT().GetAll().Return(users[:1], nil)
+			},
+			expectedStatusCode:  200,
+			expectedContentType: "application/x-ndjson",
+			expectedResponseBody: `{"id":"3d624904890861643c610064","username":"admin","password":"$2a$12$QSEvrvXWWegdupNz73bYeedLkOl5VRUNWT8iG2hGeeN5Z1FjlfBxq","isAdmin":true}
+`},
+		{
+			name:                "get not acceptable when no offered type is accepted",
+			accept:              "application/xml",
+			mockBehavior:        func(s *mock_ports.MockIUserService) {},
+			expectedStatusCode:  406,
+			expectedContentType: "application/json",
+			expectedResponseBody: `{"Errors":["The response can only be one of: application/json, text/csv, application/x-ndjson"]}
 `},
 	}
 	for _, testCase := range testTable {
@@ -71,12 +119,16 @@ null
 			// Create Request
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("GET", "/api/users", nil)
+			if len(testCase.accept) > 0 {
+				req.Header.Set("Accept", testCase.accept)
+			}
 
 			// Make Request
 			r.ServeHTTP(w, req)
 
 			// Assert
 			assert.Equal(t, w.Code, testCase.expectedStatusCode)
+			assert.Equal(t, w.Header().Get("Content-Type"), testCase.expectedContentType)
 			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
 		})
 	}
@@ -114,7 +166,6 @@ func TestUserHandler_Get(t *testing.T) {
 			},
 			expectedStatusCode: 500,
 			expectedResponseBody: `{"Errors":["\"Errors\": [\"mongo: no documents in result\"]"]}
-null
 `},
 	}
 	for _, testCase := range testTable {
@@ -254,7 +305,6 @@ func TestUserHandler_Create_CreateNewUser(t *testing.T) {
 			},
 			expectedStatusCode: 500,
 			expectedResponseBody: `{"Errors":["error create new user in database"]}
-""
 `},
 	}
 	for _, testCase := range testTable {
@@ -10,7 +10,27 @@ type IHealthHandler interface {
 
 type ISalaryHandler interface {
 	UploadFile(w http.ResponseWriter, r *http.Request)
+	Rollback(w http.ResponseWriter, r *http.Request)
+	GetAllVersions(w http.ResponseWriter, r *http.Request)
+	ActivateVersion(w http.ResponseWriter, r *http.Request)
+	DeleteVersion(w http.ResponseWriter, r *http.Request)
 }
+type IExchangeRateHandler interface {
+	Import(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	GetCurrencyRates(w http.ResponseWriter, r *http.Request)
+	CreateCurrencyRate(w http.ResponseWriter, r *http.Request)
+	UpdateCurrencyRate(w http.ResponseWriter, r *http.Request)
+	DeleteCurrencyRate(w http.ResponseWriter, r *http.Request)
+	GetCurrencyRateChanges(w http.ResponseWriter, r *http.Request)
+}
+
+type IImportJobHandler interface {
+	Get(w http.ResponseWriter, r *http.Request)
+	Cancel(w http.ResponseWriter, r *http.Request)
+	GetErrors(w http.ResponseWriter, r *http.Request)
+}
+
 type IUserHandler interface {
 	GetAll(w http.ResponseWriter, r *http.Request)
 	Get(w http.ResponseWriter, r *http.Request)
@@ -26,6 +46,14 @@ type IMiddlewareHandler interface {
 	CheckJWT(next http.Handler) http.Handler
 }
 
+type ITaxonomyHandler interface {
+	Seniority(w http.ResponseWriter, r *http.Request)
+}
+
 type IFilterHandler interface {
 	Filter(w http.ResponseWriter, r *http.Request)
+	Stats(w http.ResponseWriter, r *http.Request)
+	Groups(w http.ResponseWriter, r *http.Request)
+	Histogram(w http.ResponseWriter, r *http.Request)
+	Export(w http.ResponseWriter, r *http.Request)
 }
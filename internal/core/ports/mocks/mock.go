@@ -5,12 +5,14 @@
 package mock_ports
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
+	gomock "github.com/golang/mock/gomock"
 	domain "github.com/inkoba/app_for_HR/internal/core/domain"
 	request "github.com/inkoba/app_for_HR/internal/core/domain/request"
 	response "github.com/inkoba/app_for_HR/internal/core/domain/response"
-	gomock "github.com/golang/mock/gomock"
 )
 
 // MockIHealthService is a mock of IHealthService interface.
@@ -207,34 +209,436 @@ func (m *MockISalaryService) EXPECT() *MockISalaryServiceMockRecorder {
 	return m.recorder
 }
 
+// ActivateVersion mocks base method.
+func (m *MockISalaryService) ActivateVersion(id string) (*domain.SalaryDataset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateVersion", id)
+	ret0, _ := ret[0].(*domain.SalaryDataset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateVersion indicates an expected call of ActivateVersion.
+func (mr *MockISalaryServiceMockRecorder) ActivateVersion(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateVersion", reflect.TypeOf((*MockISalaryService)(nil).ActivateVersion), id)
+}
+
+// CountSalariesByFilter mocks base method.
+func (m *MockISalaryService) CountSalariesByFilter(page *request.SalaryPageRequest) (*response.SalariesPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSalariesByFilter", page)
+	ret0, _ := ret[0].(*response.SalariesPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSalariesByFilter indicates an expected call of CountSalariesByFilter.
+func (mr *MockISalaryServiceMockRecorder) CountSalariesByFilter(page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSalariesByFilter", reflect.TypeOf((*MockISalaryService)(nil).CountSalariesByFilter), page)
+}
+
 // Create mocks base method.
-func (m *MockISalaryService) Create(file []byte) (*response.SalaryUploadReport, error) {
+func (m *MockISalaryService) Create(ctx context.Context, upload *request.SalaryUpload) (*response.SalaryUploadReport, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", file)
+	ret := m.ctrl.Call(m, "Create", ctx, upload)
 	ret0, _ := ret[0].(*response.SalaryUploadReport)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockISalaryServiceMockRecorder) Create(file interface{}) *gomock.Call {
+func (mr *MockISalaryServiceMockRecorder) Create(ctx, upload interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockISalaryService)(nil).Create), file)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockISalaryService)(nil).Create), ctx, upload)
 }
 
-// GetSalariesByFilter mocks base method.
-func (m *MockISalaryService) GetSalariesByFilter(filterSalary *request.ConditionForFilteringSalaries) ([]*response.SalariesResponse, error) {
+// DeleteVersion mocks base method.
+func (m *MockISalaryService) DeleteVersion(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVersion", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVersion indicates an expected call of DeleteVersion.
+func (mr *MockISalaryServiceMockRecorder) DeleteVersion(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVersion", reflect.TypeOf((*MockISalaryService)(nil).DeleteVersion), id)
+}
+
+// ExportSalaries mocks base method.
+func (m *MockISalaryService) ExportSalaries(exportRequest *request.SalaryExportRequest, each func(*response.SalaryExportRow) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportSalaries", exportRequest, each)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportSalaries indicates an expected call of ExportSalaries.
+func (mr *MockISalaryServiceMockRecorder) ExportSalaries(exportRequest, each interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportSalaries", reflect.TypeOf((*MockISalaryService)(nil).ExportSalaries), exportRequest, each)
+}
+
+// GetAllVersions mocks base method.
+func (m *MockISalaryService) GetAllVersions() ([]*domain.SalaryDataset, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSalariesByFilter", filterSalary)
-	ret0, _ := ret[0].([]*response.SalariesResponse)
+	ret := m.ctrl.Call(m, "GetAllVersions")
+	ret0, _ := ret[0].([]*domain.SalaryDataset)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetAllVersions indicates an expected call of GetAllVersions.
+func (mr *MockISalaryServiceMockRecorder) GetAllVersions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllVersions", reflect.TypeOf((*MockISalaryService)(nil).GetAllVersions))
+}
+
+// GetSalariesByFilter mocks base method.
+func (m *MockISalaryService) GetSalariesByFilter(page *request.SalaryPageRequest, each func(*response.SalariesResponse) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalariesByFilter", page, each)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
 // GetSalariesByFilter indicates an expected call of GetSalariesByFilter.
-func (mr *MockISalaryServiceMockRecorder) GetSalariesByFilter(filterSalary interface{}) *gomock.Call {
+func (mr *MockISalaryServiceMockRecorder) GetSalariesByFilter(page, each interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalariesByFilter", reflect.TypeOf((*MockISalaryService)(nil).GetSalariesByFilter), page, each)
+}
+
+// GetSalaryGroups mocks base method.
+func (m *MockISalaryService) GetSalaryGroups(groupsRequest *request.SalaryGroupsRequest) (*response.SalaryGroupsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalaryGroups", groupsRequest)
+	ret0, _ := ret[0].(*response.SalaryGroupsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalaryGroups indicates an expected call of GetSalaryGroups.
+func (mr *MockISalaryServiceMockRecorder) GetSalaryGroups(groupsRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalaryGroups", reflect.TypeOf((*MockISalaryService)(nil).GetSalaryGroups), groupsRequest)
+}
+
+// GetSalaryHistogram mocks base method.
+func (m *MockISalaryService) GetSalaryHistogram(histogramRequest *request.SalaryHistogramRequest) (*response.SalaryHistogramResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalaryHistogram", histogramRequest)
+	ret0, _ := ret[0].(*response.SalaryHistogramResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalaryHistogram indicates an expected call of GetSalaryHistogram.
+func (mr *MockISalaryServiceMockRecorder) GetSalaryHistogram(histogramRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalaryHistogram", reflect.TypeOf((*MockISalaryService)(nil).GetSalaryHistogram), histogramRequest)
+}
+
+// GetSalaryStats mocks base method.
+func (m *MockISalaryService) GetSalaryStats(statsRequest *request.SalaryStatsRequest) (*response.SalaryStatsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalaryStats", statsRequest)
+	ret0, _ := ret[0].(*response.SalaryStatsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalaryStats indicates an expected call of GetSalaryStats.
+func (mr *MockISalaryServiceMockRecorder) GetSalaryStats(statsRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalaryStats", reflect.TypeOf((*MockISalaryService)(nil).GetSalaryStats), statsRequest)
+}
+
+// Rollback mocks base method.
+func (m *MockISalaryService) Rollback() (*domain.SalaryDataset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback")
+	ret0, _ := ret[0].(*domain.SalaryDataset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockISalaryServiceMockRecorder) Rollback() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockISalaryService)(nil).Rollback))
+}
+
+// MockIImportJobService is a mock of IImportJobService interface.
+type MockIImportJobService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIImportJobServiceMockRecorder
+}
+
+// MockIImportJobServiceMockRecorder is the mock recorder for MockIImportJobService.
+type MockIImportJobServiceMockRecorder struct {
+	mock *MockIImportJobService
+}
+
+// NewMockIImportJobService creates a new mock instance.
+func NewMockIImportJobService(ctrl *gomock.Controller) *MockIImportJobService {
+	mock := &MockIImportJobService{ctrl: ctrl}
+	mock.recorder = &MockIImportJobServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIImportJobService) EXPECT() *MockIImportJobServiceMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockIImportJobService) Cancel(id string) (*domain.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", id)
+	ret0, _ := ret[0].(*domain.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockIImportJobServiceMockRecorder) Cancel(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockIImportJobService)(nil).Cancel), id)
+}
+
+// Get mocks base method.
+func (m *MockIImportJobService) Get(id string) (*domain.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", id)
+	ret0, _ := ret[0].(*domain.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIImportJobServiceMockRecorder) Get(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIImportJobService)(nil).Get), id)
+}
+
+// Start mocks base method.
+func (m *MockIImportJobService) Start(upload *request.SalaryUpload) (*domain.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", upload)
+	ret0, _ := ret[0].(*domain.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockIImportJobServiceMockRecorder) Start(upload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockIImportJobService)(nil).Start), upload)
+}
+
+// MockIExchangeRateService is a mock of IExchangeRateService interface.
+type MockIExchangeRateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIExchangeRateServiceMockRecorder
+}
+
+// MockIExchangeRateServiceMockRecorder is the mock recorder for MockIExchangeRateService.
+type MockIExchangeRateServiceMockRecorder struct {
+	mock *MockIExchangeRateService
+}
+
+// NewMockIExchangeRateService creates a new mock instance.
+func NewMockIExchangeRateService(ctrl *gomock.Controller) *MockIExchangeRateService {
+	mock := &MockIExchangeRateService{ctrl: ctrl}
+	mock.recorder = &MockIExchangeRateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIExchangeRateService) EXPECT() *MockIExchangeRateServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateCurrencyRate mocks base method.
+func (m *MockIExchangeRateService) CreateCurrencyRate(rateRequest *request.CurrencyRateRequest, user string) (*domain.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCurrencyRate", rateRequest, user)
+	ret0, _ := ret[0].(*domain.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCurrencyRate indicates an expected call of CreateCurrencyRate.
+func (mr *MockIExchangeRateServiceMockRecorder) CreateCurrencyRate(rateRequest, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCurrencyRate", reflect.TypeOf((*MockIExchangeRateService)(nil).CreateCurrencyRate), rateRequest, user)
+}
+
+// DeleteCurrencyRate mocks base method.
+func (m *MockIExchangeRateService) DeleteCurrencyRate(currency, user string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCurrencyRate", currency, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCurrencyRate indicates an expected call of DeleteCurrencyRate.
+func (mr *MockIExchangeRateServiceMockRecorder) DeleteCurrencyRate(currency, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCurrencyRate", reflect.TypeOf((*MockIExchangeRateService)(nil).DeleteCurrencyRate), currency, user)
+}
+
+// GetCurrencyRateChanges mocks base method.
+func (m *MockIExchangeRateService) GetCurrencyRateChanges(currency string) ([]*domain.CurrencyRateChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyRateChanges", currency)
+	ret0, _ := ret[0].([]*domain.CurrencyRateChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyRateChanges indicates an expected call of GetCurrencyRateChanges.
+func (mr *MockIExchangeRateServiceMockRecorder) GetCurrencyRateChanges(currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyRateChanges", reflect.TypeOf((*MockIExchangeRateService)(nil).GetCurrencyRateChanges), currency)
+}
+
+// GetCurrencyRates mocks base method.
+func (m *MockIExchangeRateService) GetCurrencyRates() ([]*domain.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyRates")
+	ret0, _ := ret[0].([]*domain.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyRates indicates an expected call of GetCurrencyRates.
+func (mr *MockIExchangeRateServiceMockRecorder) GetCurrencyRates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyRates", reflect.TypeOf((*MockIExchangeRateService)(nil).GetCurrencyRates))
+}
+
+// GetRates mocks base method.
+func (m *MockIExchangeRateService) GetRates(date *time.Time) (*domain.ExchangeRates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRates", date)
+	ret0, _ := ret[0].(*domain.ExchangeRates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRates indicates an expected call of GetRates.
+func (mr *MockIExchangeRateServiceMockRecorder) GetRates(date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRates", reflect.TypeOf((*MockIExchangeRateService)(nil).GetRates), date)
+}
+
+// Import mocks base method.
+func (m *MockIExchangeRateService) Import(ratesImport *request.ExchangeRatesImport) (*response.ExchangeRatesImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ratesImport)
+	ret0, _ := ret[0].(*response.ExchangeRatesImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockIExchangeRateServiceMockRecorder) Import(ratesImport interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockIExchangeRateService)(nil).Import), ratesImport)
+}
+
+// UpdateCurrencyRate mocks base method.
+func (m *MockIExchangeRateService) UpdateCurrencyRate(currency string, rateRequest *request.CurrencyRateRequest, user string) (*domain.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCurrencyRate", currency, rateRequest, user)
+	ret0, _ := ret[0].(*domain.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCurrencyRate indicates an expected call of UpdateCurrencyRate.
+func (mr *MockIExchangeRateServiceMockRecorder) UpdateCurrencyRate(currency, rateRequest, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCurrencyRate", reflect.TypeOf((*MockIExchangeRateService)(nil).UpdateCurrencyRate), currency, rateRequest, user)
+}
+
+// MockIColumnMappingService is a mock of IColumnMappingService interface.
+type MockIColumnMappingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIColumnMappingServiceMockRecorder
+}
+
+// MockIColumnMappingServiceMockRecorder is the mock recorder for MockIColumnMappingService.
+type MockIColumnMappingServiceMockRecorder struct {
+	mock *MockIColumnMappingService
+}
+
+// NewMockIColumnMappingService creates a new mock instance.
+func NewMockIColumnMappingService(ctrl *gomock.Controller) *MockIColumnMappingService {
+	mock := &MockIColumnMappingService{ctrl: ctrl}
+	mock.recorder = &MockIColumnMappingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIColumnMappingService) EXPECT() *MockIColumnMappingServiceMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method.
+func (m *MockIColumnMappingService) Resolve(header []string) (*domain.ColumnMapping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", header)
+	ret0, _ := ret[0].(*domain.ColumnMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockIColumnMappingServiceMockRecorder) Resolve(header interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockIColumnMappingService)(nil).Resolve), header)
+}
+
+// MockISeniorityTaxonomyService is a mock of ISeniorityTaxonomyService interface.
+type MockISeniorityTaxonomyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockISeniorityTaxonomyServiceMockRecorder
+}
+
+// MockISeniorityTaxonomyServiceMockRecorder is the mock recorder for MockISeniorityTaxonomyService.
+type MockISeniorityTaxonomyServiceMockRecorder struct {
+	mock *MockISeniorityTaxonomyService
+}
+
+// NewMockISeniorityTaxonomyService creates a new mock instance.
+func NewMockISeniorityTaxonomyService(ctrl *gomock.Controller) *MockISeniorityTaxonomyService {
+	mock := &MockISeniorityTaxonomyService{ctrl: ctrl}
+	mock.recorder = &MockISeniorityTaxonomyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISeniorityTaxonomyService) EXPECT() *MockISeniorityTaxonomyServiceMockRecorder {
+	return m.recorder
+}
+
+// GetSeniority mocks base method.
+func (m *MockISeniorityTaxonomyService) GetSeniority() *domain.SeniorityTaxonomy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeniority")
+	ret0, _ := ret[0].(*domain.SeniorityTaxonomy)
+	return ret0
+}
+
+// GetSeniority indicates an expected call of GetSeniority.
+func (mr *MockISeniorityTaxonomyServiceMockRecorder) GetSeniority() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalariesByFilter", reflect.TypeOf((*MockISalaryService)(nil).GetSalariesByFilter), filterSalary)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeniority", reflect.TypeOf((*MockISeniorityTaxonomyService)(nil).GetSeniority))
 }
 
 // MockICryptoService is a mock of ICryptoService interface.
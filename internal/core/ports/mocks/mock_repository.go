@@ -6,10 +6,12 @@ package mock_ports
 
 import (
 	reflect "reflect"
+	time "time"
 
+	gomock "github.com/golang/mock/gomock"
 	domain "github.com/inkoba/app_for_HR/internal/core/domain"
 	request "github.com/inkoba/app_for_HR/internal/core/domain/request"
-	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MockIUserRepository is a mock of IUserRepository interface.
@@ -132,6 +134,49 @@ func (m *MockISalaryRepository) EXPECT() *MockISalaryRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ActivateDataset mocks base method.
+func (m *MockISalaryRepository) ActivateDataset(id primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateDataset", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ActivateDataset indicates an expected call of ActivateDataset.
+func (mr *MockISalaryRepositoryMockRecorder) ActivateDataset(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateDataset", reflect.TypeOf((*MockISalaryRepository)(nil).ActivateDataset), id)
+}
+
+// CopyActiveDataset mocks base method.
+func (m *MockISalaryRepository) CopyActiveDataset(id primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyActiveDataset", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyActiveDataset indicates an expected call of CopyActiveDataset.
+func (mr *MockISalaryRepositoryMockRecorder) CopyActiveDataset(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyActiveDataset", reflect.TypeOf((*MockISalaryRepository)(nil).CopyActiveDataset), id)
+}
+
+// CountFilteredSalaries mocks base method.
+func (m *MockISalaryRepository) CountFilteredSalaries(filterSalary *request.ConditionForFilteringSalaries) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFilteredSalaries", filterSalary)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFilteredSalaries indicates an expected call of CountFilteredSalaries.
+func (mr *MockISalaryRepositoryMockRecorder) CountFilteredSalaries(filterSalary interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFilteredSalaries", reflect.TypeOf((*MockISalaryRepository)(nil).CountFilteredSalaries), filterSalary)
+}
+
 // Create mocks base method.
 func (m *MockISalaryRepository) Create(salaries []*domain.Salary) error {
 	m.ctrl.T.Helper()
@@ -146,33 +191,363 @@ func (mr *MockISalaryRepositoryMockRecorder) Create(salaries interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockISalaryRepository)(nil).Create), salaries)
 }
 
-// DeleteAll mocks base method.
-func (m *MockISalaryRepository) DeleteAll() error {
+// CreateDataset mocks base method.
+func (m *MockISalaryRepository) CreateDataset(upload *request.SalaryUpload) (*domain.SalaryDataset, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAll")
+	ret := m.ctrl.Call(m, "CreateDataset", upload)
+	ret0, _ := ret[0].(*domain.SalaryDataset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDataset indicates an expected call of CreateDataset.
+func (mr *MockISalaryRepositoryMockRecorder) CreateDataset(upload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDataset", reflect.TypeOf((*MockISalaryRepository)(nil).CreateDataset), upload)
+}
+
+// DeleteDataset mocks base method.
+func (m *MockISalaryRepository) DeleteDataset(id primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDataset", id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteAll indicates an expected call of DeleteAll.
-func (mr *MockISalaryRepositoryMockRecorder) DeleteAll() *gomock.Call {
+// DeleteDataset indicates an expected call of DeleteDataset.
+func (mr *MockISalaryRepositoryMockRecorder) DeleteDataset(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockISalaryRepository)(nil).DeleteAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataset", reflect.TypeOf((*MockISalaryRepository)(nil).DeleteDataset), id)
 }
 
-// GetFilteredSalaries mocks base method.
-func (m *MockISalaryRepository) GetFilteredSalaries(filterSalary *request.ConditionForFilteringSalaries) ([]*domain.Salary, error) {
+// GetActiveDataset mocks base method.
+func (m *MockISalaryRepository) GetActiveDataset() (*domain.SalaryDataset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveDataset")
+	ret0, _ := ret[0].(*domain.SalaryDataset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveDataset indicates an expected call of GetActiveDataset.
+func (mr *MockISalaryRepositoryMockRecorder) GetActiveDataset() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveDataset", reflect.TypeOf((*MockISalaryRepository)(nil).GetActiveDataset))
+}
+
+// GetAllDatasets mocks base method.
+func (m *MockISalaryRepository) GetAllDatasets() ([]*domain.SalaryDataset, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilteredSalaries", filterSalary)
-	ret0, _ := ret[0].([]*domain.Salary)
+	ret := m.ctrl.Call(m, "GetAllDatasets")
+	ret0, _ := ret[0].([]*domain.SalaryDataset)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetAllDatasets indicates an expected call of GetAllDatasets.
+func (mr *MockISalaryRepositoryMockRecorder) GetAllDatasets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllDatasets", reflect.TypeOf((*MockISalaryRepository)(nil).GetAllDatasets))
+}
+
+// GetDataset mocks base method.
+func (m *MockISalaryRepository) GetDataset(id primitive.ObjectID) (*domain.SalaryDataset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataset", id)
+	ret0, _ := ret[0].(*domain.SalaryDataset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDataset indicates an expected call of GetDataset.
+func (mr *MockISalaryRepositoryMockRecorder) GetDataset(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataset", reflect.TypeOf((*MockISalaryRepository)(nil).GetDataset), id)
+}
+
+// GetFilteredSalaries mocks base method.
+func (m *MockISalaryRepository) GetFilteredSalaries(page *request.SalaryPageRequest, each func(*domain.Salary) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilteredSalaries", page, each)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
 // GetFilteredSalaries indicates an expected call of GetFilteredSalaries.
-func (mr *MockISalaryRepositoryMockRecorder) GetFilteredSalaries(filterSalary interface{}) *gomock.Call {
+func (mr *MockISalaryRepositoryMockRecorder) GetFilteredSalaries(page, each interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilteredSalaries", reflect.TypeOf((*MockISalaryRepository)(nil).GetFilteredSalaries), page, each)
+}
+
+// GetSalaryGroupStats mocks base method.
+func (m *MockISalaryRepository) GetSalaryGroupStats(filterSalary *request.ConditionForFilteringSalaries, groupBy []string) ([]*domain.SalaryGroupStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalaryGroupStats", filterSalary, groupBy)
+	ret0, _ := ret[0].([]*domain.SalaryGroupStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalaryGroupStats indicates an expected call of GetSalaryGroupStats.
+func (mr *MockISalaryRepositoryMockRecorder) GetSalaryGroupStats(filterSalary, groupBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalaryGroupStats", reflect.TypeOf((*MockISalaryRepository)(nil).GetSalaryGroupStats), filterSalary, groupBy)
+}
+
+// GetSalaryHistogram mocks base method.
+func (m *MockISalaryRepository) GetSalaryHistogram(filterSalary *request.ConditionForFilteringSalaries, layout *domain.HistogramLayout) (*domain.SalaryHistogram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalaryHistogram", filterSalary, layout)
+	ret0, _ := ret[0].(*domain.SalaryHistogram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalaryHistogram indicates an expected call of GetSalaryHistogram.
+func (mr *MockISalaryRepositoryMockRecorder) GetSalaryHistogram(filterSalary, layout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalaryHistogram", reflect.TypeOf((*MockISalaryRepository)(nil).GetSalaryHistogram), filterSalary, layout)
+}
+
+// GetSalaryStats mocks base method.
+func (m *MockISalaryRepository) GetSalaryStats(filterSalary *request.ConditionForFilteringSalaries) (*domain.SalaryStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSalaryStats", filterSalary)
+	ret0, _ := ret[0].(*domain.SalaryStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSalaryStats indicates an expected call of GetSalaryStats.
+func (mr *MockISalaryRepositoryMockRecorder) GetSalaryStats(filterSalary interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSalaryStats", reflect.TypeOf((*MockISalaryRepository)(nil).GetSalaryStats), filterSalary)
+}
+
+// MigrateLegacySalaries mocks base method.
+func (m *MockISalaryRepository) MigrateLegacySalaries(usdRates map[string]float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateLegacySalaries", usdRates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MigrateLegacySalaries indicates an expected call of MigrateLegacySalaries.
+func (mr *MockISalaryRepositoryMockRecorder) MigrateLegacySalaries(usdRates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateLegacySalaries", reflect.TypeOf((*MockISalaryRepository)(nil).MigrateLegacySalaries), usdRates)
+}
+
+// MigrateLegacySeniority mocks base method.
+func (m *MockISalaryRepository) MigrateLegacySeniority(taxonomy *domain.SeniorityTaxonomy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateLegacySeniority", taxonomy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MigrateLegacySeniority indicates an expected call of MigrateLegacySeniority.
+func (mr *MockISalaryRepositoryMockRecorder) MigrateLegacySeniority(taxonomy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateLegacySeniority", reflect.TypeOf((*MockISalaryRepository)(nil).MigrateLegacySeniority), taxonomy)
+}
+
+// RollbackDataset mocks base method.
+func (m *MockISalaryRepository) RollbackDataset() (*domain.SalaryDataset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackDataset")
+	ret0, _ := ret[0].(*domain.SalaryDataset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackDataset indicates an expected call of RollbackDataset.
+func (mr *MockISalaryRepositoryMockRecorder) RollbackDataset() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackDataset", reflect.TypeOf((*MockISalaryRepository)(nil).RollbackDataset))
+}
+
+// Upsert mocks base method.
+func (m *MockISalaryRepository) Upsert(salaries []*domain.Salary) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", salaries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockISalaryRepositoryMockRecorder) Upsert(salaries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockISalaryRepository)(nil).Upsert), salaries)
+}
+
+// MockIExchangeRateRepository is a mock of IExchangeRateRepository interface.
+type MockIExchangeRateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIExchangeRateRepositoryMockRecorder
+}
+
+// MockIExchangeRateRepositoryMockRecorder is the mock recorder for MockIExchangeRateRepository.
+type MockIExchangeRateRepositoryMockRecorder struct {
+	mock *MockIExchangeRateRepository
+}
+
+// NewMockIExchangeRateRepository creates a new mock instance.
+func NewMockIExchangeRateRepository(ctrl *gomock.Controller) *MockIExchangeRateRepository {
+	mock := &MockIExchangeRateRepository{ctrl: ctrl}
+	mock.recorder = &MockIExchangeRateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIExchangeRateRepository) EXPECT() *MockIExchangeRateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddCurrencyRateChange mocks base method.
+func (m *MockIExchangeRateRepository) AddCurrencyRateChange(change *domain.CurrencyRateChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCurrencyRateChange", change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCurrencyRateChange indicates an expected call of AddCurrencyRateChange.
+func (mr *MockIExchangeRateRepositoryMockRecorder) AddCurrencyRateChange(change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCurrencyRateChange", reflect.TypeOf((*MockIExchangeRateRepository)(nil).AddCurrencyRateChange), change)
+}
+
+// CreateCurrencyRate mocks base method.
+func (m *MockIExchangeRateRepository) CreateCurrencyRate(rate *domain.CurrencyRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCurrencyRate", rate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCurrencyRate indicates an expected call of CreateCurrencyRate.
+func (mr *MockIExchangeRateRepositoryMockRecorder) CreateCurrencyRate(rate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCurrencyRate", reflect.TypeOf((*MockIExchangeRateRepository)(nil).CreateCurrencyRate), rate)
+}
+
+// DeleteCurrencyRateChange mocks base method.
+func (m *MockIExchangeRateRepository) DeleteCurrencyRateChange(id primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCurrencyRateChange", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCurrencyRateChange indicates an expected call of DeleteCurrencyRateChange.
+func (mr *MockIExchangeRateRepositoryMockRecorder) DeleteCurrencyRateChange(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCurrencyRateChange", reflect.TypeOf((*MockIExchangeRateRepository)(nil).DeleteCurrencyRateChange), id)
+}
+
+// DeleteCurrencyRate mocks base method.
+func (m *MockIExchangeRateRepository) DeleteCurrencyRate(currency string) (*domain.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCurrencyRate", currency)
+	ret0, _ := ret[0].(*domain.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCurrencyRate indicates an expected call of DeleteCurrencyRate.
+func (mr *MockIExchangeRateRepositoryMockRecorder) DeleteCurrencyRate(currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCurrencyRate", reflect.TypeOf((*MockIExchangeRateRepository)(nil).DeleteCurrencyRate), currency)
+}
+
+// GetCurrencyRateChanges mocks base method.
+func (m *MockIExchangeRateRepository) GetCurrencyRateChanges(currency string) ([]*domain.CurrencyRateChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyRateChanges", currency)
+	ret0, _ := ret[0].([]*domain.CurrencyRateChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyRateChanges indicates an expected call of GetCurrencyRateChanges.
+func (mr *MockIExchangeRateRepositoryMockRecorder) GetCurrencyRateChanges(currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyRateChanges", reflect.TypeOf((*MockIExchangeRateRepository)(nil).GetCurrencyRateChanges), currency)
+}
+
+// GetCurrencyRate mocks base method.
+func (m *MockIExchangeRateRepository) GetCurrencyRate(currency string) (*domain.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyRate", currency)
+	ret0, _ := ret[0].(*domain.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyRate indicates an expected call of GetCurrencyRate.
+func (mr *MockIExchangeRateRepositoryMockRecorder) GetCurrencyRate(currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyRate", reflect.TypeOf((*MockIExchangeRateRepository)(nil).GetCurrencyRate), currency)
+}
+
+// GetCurrencyRates mocks base method.
+func (m *MockIExchangeRateRepository) GetCurrencyRates() ([]*domain.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrencyRates")
+	ret0, _ := ret[0].([]*domain.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrencyRates indicates an expected call of GetCurrencyRates.
+func (mr *MockIExchangeRateRepositoryMockRecorder) GetCurrencyRates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyRates", reflect.TypeOf((*MockIExchangeRateRepository)(nil).GetCurrencyRates))
+}
+
+// GetRates mocks base method.
+func (m *MockIExchangeRateRepository) GetRates(date time.Time) (*domain.ExchangeRates, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRates", date)
+	ret0, _ := ret[0].(*domain.ExchangeRates)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRates indicates an expected call of GetRates.
+func (mr *MockIExchangeRateRepositoryMockRecorder) GetRates(date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRates", reflect.TypeOf((*MockIExchangeRateRepository)(nil).GetRates), date)
+}
+
+// SaveRates mocks base method.
+func (m *MockIExchangeRateRepository) SaveRates(days []*domain.ExchangeRates) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRates", days)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRates indicates an expected call of SaveRates.
+func (mr *MockIExchangeRateRepositoryMockRecorder) SaveRates(days interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRates", reflect.TypeOf((*MockIExchangeRateRepository)(nil).SaveRates), days)
+}
+
+// UpdateCurrencyRate mocks base method.
+func (m *MockIExchangeRateRepository) UpdateCurrencyRate(rate *domain.CurrencyRate) (*domain.CurrencyRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCurrencyRate", rate)
+	ret0, _ := ret[0].(*domain.CurrencyRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCurrencyRate indicates an expected call of UpdateCurrencyRate.
+func (mr *MockIExchangeRateRepositoryMockRecorder) UpdateCurrencyRate(rate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilteredSalaries", reflect.TypeOf((*MockISalaryRepository)(nil).GetFilteredSalaries), filterSalary)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCurrencyRate", reflect.TypeOf((*MockIExchangeRateRepository)(nil).UpdateCurrencyRate), rate)
 }
 
 // MockIHealthRepository is a mock of IHealthRepository interface.
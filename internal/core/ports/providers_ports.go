@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"io"
+)
+
+//go:generate mockgen -source=providers_ports.go -destination=mocks/mock_provider.go -package=mock_ports
+
+// IExchangeRateProvider supplies the exchange rates used for every salary conversion
+type IExchangeRateProvider interface {
+	GetRates() (*domain.ExchangeRates, error)
+}
+
+// IExchangeRatesParser reads the days of an exchange rates file, Format returns the format of the file
+// given by the name when it is empty
+type IExchangeRatesParser interface {
+	Format(format string, fileName string) (string, error)
+	Parse(reader io.Reader, format string) ([]*domain.ExchangeRates, error)
+}
@@ -3,6 +3,8 @@ package ports
 import (
 	"github.com/inkoba/app_for_HR/internal/core/domain"
 	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
 )
 
 //go:generate mockgen -source=repositories_ports.go -destination=mocks/mock_repository.go
@@ -15,9 +17,37 @@ type IUserRepository interface {
 	GetUserByUsername(username string) (*domain.User, error)
 }
 type ISalaryRepository interface {
+	CreateDataset(upload *request.SalaryUpload) (*domain.SalaryDataset, error)
+	CopyActiveDataset(id primitive.ObjectID) error
+	GetDataset(id primitive.ObjectID) (*domain.SalaryDataset, error)
+	GetActiveDataset() (*domain.SalaryDataset, error)
+	GetAllDatasets() ([]*domain.SalaryDataset, error)
+	ActivateDataset(id primitive.ObjectID) error
+	DeleteDataset(id primitive.ObjectID) error
+	RollbackDataset() (*domain.SalaryDataset, error)
 	Create(salaries []*domain.Salary) error
-	DeleteAll() error
-	GetFilteredSalaries(filterSalary *request.ConditionForFilteringSalaries) ([]*domain.Salary, error)
+	Upsert(salaries []*domain.Salary) error
+	CountFilteredSalaries(filterSalary *request.ConditionForFilteringSalaries) (int64, error)
+	GetFilteredSalaries(page *request.SalaryPageRequest, each func(salary *domain.Salary) error) error
+	GetSalaryStats(filterSalary *request.ConditionForFilteringSalaries) (*domain.SalaryStats, error)
+	GetSalaryGroupStats(filterSalary *request.ConditionForFilteringSalaries, groupBy []string) ([]*domain.SalaryGroupStats, error)
+	GetSalaryHistogram(filterSalary *request.ConditionForFilteringSalaries, layout *domain.HistogramLayout) (*domain.SalaryHistogram, error)
+	MigrateLegacySalaries(usdRates map[string]float64) error
+	MigrateLegacySeniority(taxonomy *domain.SeniorityTaxonomy) error
+}
+
+// IExchangeRateRepository keeps the exchange rates of every day and the currency rates set by admins with their changes
+type IExchangeRateRepository interface {
+	SaveRates(days []*domain.ExchangeRates) error
+	GetRates(date time.Time) (*domain.ExchangeRates, error)
+	GetCurrencyRates() ([]*domain.CurrencyRate, error)
+	GetCurrencyRate(currency string) (*domain.CurrencyRate, error)
+	CreateCurrencyRate(rate *domain.CurrencyRate) error
+	UpdateCurrencyRate(rate *domain.CurrencyRate) (*domain.CurrencyRate, error)
+	DeleteCurrencyRate(currency string) (*domain.CurrencyRate, error)
+	AddCurrencyRateChange(change *domain.CurrencyRateChange) error
+	DeleteCurrencyRateChange(id primitive.ObjectID) error
+	GetCurrencyRateChanges(currency string) ([]*domain.CurrencyRateChange, error)
 }
 
 type IHealthRepository interface {
@@ -1,9 +1,11 @@
 package ports
 
 import (
+	"context"
 	"github.com/inkoba/app_for_HR/internal/core/domain"
 	"github.com/inkoba/app_for_HR/internal/core/domain/request"
 	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	"time"
 )
 
 //go:generate mockgen -source=services_ports.go -destination=mocks/mock.go
@@ -22,8 +24,41 @@ type IAuthService interface {
 	IsValidUser(username string, password string) error
 }
 type ISalaryService interface {
-	Create(file []byte) (*response.SalaryUploadReport, error)
-	GetSalariesByFilter(filterSalary *request.ConditionForFilteringSalaries) ([]*response.SalariesResponse, error)
+	Create(ctx context.Context, upload *request.SalaryUpload) (*response.SalaryUploadReport, error)
+	Rollback() (*domain.SalaryDataset, error)
+	GetAllVersions() ([]*domain.SalaryDataset, error)
+	ActivateVersion(id string) (*domain.SalaryDataset, error)
+	DeleteVersion(id string) error
+	CountSalariesByFilter(page *request.SalaryPageRequest) (*response.SalariesPage, error)
+	GetSalariesByFilter(page *request.SalaryPageRequest, each func(salary *response.SalariesResponse) error) error
+	ExportSalaries(exportRequest *request.SalaryExportRequest, each func(row *response.SalaryExportRow) error) error
+	GetSalaryStats(statsRequest *request.SalaryStatsRequest) (*response.SalaryStatsResponse, error)
+	GetSalaryGroups(groupsRequest *request.SalaryGroupsRequest) (*response.SalaryGroupsResponse, error)
+	GetSalaryHistogram(histogramRequest *request.SalaryHistogramRequest) (*response.SalaryHistogramResponse, error)
+}
+
+type IImportJobService interface {
+	Start(upload *request.SalaryUpload) (*domain.ImportJob, error)
+	Get(id string) (*domain.ImportJob, error)
+	Cancel(id string) (*domain.ImportJob, error)
+}
+
+type IExchangeRateService interface {
+	Import(ratesImport *request.ExchangeRatesImport) (*response.ExchangeRatesImportReport, error)
+	GetRates(date *time.Time) (*domain.ExchangeRates, error)
+	GetCurrencyRates() ([]*domain.CurrencyRate, error)
+	CreateCurrencyRate(rateRequest *request.CurrencyRateRequest, user string) (*domain.CurrencyRate, error)
+	UpdateCurrencyRate(currency string, rateRequest *request.CurrencyRateRequest, user string) (*domain.CurrencyRate, error)
+	DeleteCurrencyRate(currency string, user string) error
+	GetCurrencyRateChanges(currency string) ([]*domain.CurrencyRateChange, error)
+}
+
+type IColumnMappingService interface {
+	Resolve(header []string) (*domain.ColumnMapping, error)
+}
+
+type ISeniorityTaxonomyService interface {
+	GetSeniority() *domain.SeniorityTaxonomy
 }
 
 type ICryptoService interface {
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"github.com/inkoba/app_for_HR/internal/config"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+const byteOrderMark = "\ufeff"
+
+var requiredColumns = []string{
+	domain.ColumnSalary,
+	domain.ColumnLevelOfSeniority,
+	domain.ColumnYearsTotal,
+	domain.ColumnCountry,
+	domain.ColumnLevelOfEnglish,
+}
+
+type ColumnMappingService struct {
+	editions []config.SurveyEdition
+	logger   *logrus.Logger
+}
+
+var _ ports.IColumnMappingService = (*ColumnMappingService)(nil)
+
+func NewColumnMappingService(surveyConfig config.SurveyConfig, logger *logrus.Logger) *ColumnMappingService {
+	return &ColumnMappingService{
+		surveyConfig.Editions,
+		logger,
+	}
+}
+
+// Resolve finds the first survey edition whose aliases cover all required columns of the header
+func (cs ColumnMappingService) Resolve(header []string) (*domain.ColumnMapping, error) {
+	if len(cs.editions) == 0 {
+		return nil, errors.New("No survey editions are configured")
+	}
+
+	positions := make(map[string]int, len(header))
+	for index, name := range header {
+		name = normalizeColumnName(name)
+		if _, exists := positions[name]; !exists {
+			positions[name] = index
+		}
+	}
+
+	var closest *domain.MissingColumnsError
+	for _, edition := range cs.editions {
+		mapping := domain.ColumnMapping{Edition: edition.Name, Indexes: map[string]int{}}
+		var missing []string
+
+		for column, aliases := range edition.Columns {
+			for _, alias := range aliases {
+				if index, ok := positions[normalizeColumnName(alias)]; ok {
+					mapping.Indexes[column] = index
+					break
+				}
+			}
+		}
+
+		for _, column := range requiredColumns {
+			if _, ok := mapping.Indexes[column]; !ok {
+				missing = append(missing, column)
+			}
+		}
+
+		if len(missing) == 0 {
+			cs.logger.Info("Columns of the file are mapped using survey edition ", edition.Name)
+			return &mapping, nil
+		}
+
+		if closest == nil || len(missing) < len(closest.Columns) {
+			closest = &domain.MissingColumnsError{Edition: edition.Name, Columns: missing}
+		}
+	}
+
+	return nil, closest
+}
+
+func normalizeColumnName(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, byteOrderMark)))
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"github.com/inkoba/app_for_HR/internal/config"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestColumnMappingService_Resolve(t *testing.T) {
+	surveyConfig := config.SurveyConfig{Editions: []config.SurveyEdition{
+		{
+			Name: "2022",
+			Columns: map[string][]string{
+				domain.ColumnSalary:           {"Salary", "Monthly salary"},
+				domain.ColumnLevelOfSeniority: {"Level of seniority"},
+				domain.ColumnYearsTotal:       {"Years total"},
+				domain.ColumnCountry:          {"Country"},
+				domain.ColumnLevelOfEnglish:   {"Level of English"},
+			},
+		},
+		{
+			Name: "2021",
+			Columns: map[string][]string{
+				domain.ColumnSalary:           {"What is your monthly salary?"},
+				domain.ColumnLevelOfSeniority: {"Level"},
+				domain.ColumnYearsTotal:       {"Years in IT"},
+				domain.ColumnCountry:          {"Location"},
+				domain.ColumnLevelOfEnglish:   {"English"},
+			},
+		},
+		{
+			Name:    "legacy",
+			Columns: legacyExportColumns,
+		},
+	}}
+
+	testTable := []struct {
+		name          string
+		header        []string
+		expected      *domain.ColumnMapping
+		expectedError *domain.MissingColumnsError
+	}{
+		{
+			name:   "columns are resolved by aliases regardless of order and case",
+			header: []string{"\ufeffTimestamp", " level of ENGLISH ", "Country", "Monthly salary", "Years total", "Level of seniority"},
+			expected: &domain.ColumnMapping{Edition: "2022", Indexes: map[string]int{
+				domain.ColumnSalary:           3,
+				domain.ColumnLevelOfSeniority: 5,
+				domain.ColumnYearsTotal:       4,
+				domain.ColumnCountry:          2,
+				domain.ColumnLevelOfEnglish:   1,
+			}},
+		},
+		{
+			name:   "columns are resolved using the second survey edition",
+			header: []string{"What is your monthly salary?", "Level", "Years in IT", "Location", "English"},
+			expected: &domain.ColumnMapping{Edition: "2021", Indexes: map[string]int{
+				domain.ColumnSalary:           0,
+				domain.ColumnLevelOfSeniority: 1,
+				domain.ColumnYearsTotal:       2,
+				domain.ColumnCountry:          3,
+				domain.ColumnLevelOfEnglish:   4,
+			}},
+		},
+		{
+			name:   "columns of the legacy export are resolved by their names",
+			header: legacyExportHeader(),
+			expected: &domain.ColumnMapping{Edition: "legacy", Indexes: map[string]int{
+				domain.ColumnSalary:           23,
+				domain.ColumnLevelOfSeniority: 27,
+				domain.ColumnYearsTotal:       29,
+				domain.ColumnCountry:          37,
+				domain.ColumnLevelOfEnglish:   25,
+			}},
+		},
+		{
+			name:   "get error when no column name is known",
+			header: unknownExportHeader(),
+			expectedError: &domain.MissingColumnsError{
+				Edition: "2022",
+				Columns: requiredColumns,
+			},
+		},
+		{
+			name:   "get error with the missing columns of the closest edition",
+			header: []string{"Salary", "Level of seniority", "Country"},
+			expectedError: &domain.MissingColumnsError{
+				Edition: "2022",
+				Columns: []string{domain.ColumnYearsTotal, domain.ColumnLevelOfEnglish},
+			},
+		},
+		{
+			name:   "get error when the header is empty",
+			header: nil,
+			expectedError: &domain.MissingColumnsError{
+				Edition: "2022",
+				Columns: requiredColumns,
+			},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			service := NewColumnMappingService(surveyConfig, logrus.New())
+
+			wantResult, err := service.Resolve(testCase.header)
+
+			if testCase.expectedError != nil {
+				assert.Equal(t, testCase.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+		})
+	}
+}
+
+// legacyExportColumns are the aliases of the legacy survey edition in config.yaml
+var legacyExportColumns = map[string][]string{
+	domain.ColumnSalary:           {"salary"},
+	domain.ColumnLevelOfSeniority: {"levelofseniority"},
+	domain.ColumnYearsTotal:       {"yearstotal"},
+	domain.ColumnCountry:          {"country"},
+	domain.ColumnLevelOfEnglish:   {"levelofenglish"},
+}
+
+// legacyExportHeader is the header of the legacy export, the salary fields are among other survey questions
+func legacyExportHeader() []string {
+	header := unknownExportHeader()
+	header[23], header[25], header[27], header[29], header[37] = "salary", "levelofenglish", "levelofseniority", "yearstotal", "country"
+	return header
+}
+
+// unknownExportHeader is the header of an export whose columns have no known names
+func unknownExportHeader() []string {
+	header := make([]string, 38)
+	for i := range header {
+		header[i] = fmt.Sprintf("Question %d", i+1)
+	}
+	return header
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"github.com/sirupsen/logrus"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// ExchangeRateService keeps the history of exchange rates and serves the current rates of the configured provider
+// with the currency rates set by admins in place of the provider rates
+type ExchangeRateService struct {
+	exchangeRateRepository ports.IExchangeRateRepository
+	rateProvider           ports.IExchangeRateProvider
+	ratesParser            ports.IExchangeRatesParser
+	logger                 *logrus.Logger
+}
+
+var _ ports.IExchangeRateService = (*ExchangeRateService)(nil)
+
+func NewExchangeRateService(exchangeRateRepository ports.IExchangeRateRepository, rateProvider ports.IExchangeRateProvider, ratesParser ports.IExchangeRatesParser, logger *logrus.Logger) *ExchangeRateService {
+	return &ExchangeRateService{
+		exchangeRateRepository,
+		rateProvider,
+		ratesParser,
+		logger,
+	}
+}
+
+// Import stores every day of the rates file, days that are already stored are replaced
+func (es ExchangeRateService) Import(ratesImport *request.ExchangeRatesImport) (*response.ExchangeRatesImportReport, error) {
+	format, err := es.ratesParser.Format(ratesImport.Format, ratesImport.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := es.ratesParser.Parse(ratesImport.File, format)
+	if err != nil {
+		return nil, domain.NewValidationError("The exchange rates file can not be read: %s", err)
+	}
+
+	if err := es.exchangeRateRepository.SaveRates(days); err != nil {
+		es.logger.Error(err)
+		return nil, err
+	}
+
+	currencies := map[string]bool{currencyUSD: true}
+	for _, day := range days {
+		for currency := range day.Rates {
+			currencies[currency] = true
+		}
+	}
+	report := &response.ExchangeRatesImportReport{
+		Days: len(days),
+		From: days[len(days)-1].Date.Format(dateLayout),
+		To:   days[0].Date.Format(dateLayout),
+	}
+	for currency := range currencies {
+		report.Currencies = append(report.Currencies, currency)
+	}
+	sort.Strings(report.Currencies)
+
+	es.logger.Info("Exchange rates imported from ", report.From, " to ", report.To)
+	return report, nil
+}
+
+// GetRates returns the latest stored rates on or before the date, the current rates when date is nil.
+// The current rates are also used when no rates are stored for the date.
+func (es ExchangeRateService) GetRates(date *time.Time) (*domain.ExchangeRates, error) {
+	if date != nil {
+		rates, err := es.exchangeRateRepository.GetRates(*date)
+		if err == nil {
+			return rates, nil
+		}
+		if !errors.Is(err, domain.ErrExchangeRatesNotFound) {
+			es.logger.Error(err)
+			return nil, err
+		}
+		es.logger.Warn("No exchange rates are stored on or before ", date.Format(dateLayout), ", the current rates are used")
+	}
+
+	return es.currentRates()
+}
+
+// currentRates are the rates of the provider with the currency rates set by admins, they are read on every call
+// so a change is used right away
+func (es ExchangeRateService) currentRates() (*domain.ExchangeRates, error) {
+	rates, err := es.rateProvider.GetRates()
+	if err != nil {
+		es.logger.Error(err)
+		return nil, err
+	}
+	currencyRates, err := es.exchangeRateRepository.GetCurrencyRates()
+	if err != nil {
+		es.logger.Error(err)
+		return nil, err
+	}
+	if len(currencyRates) == 0 {
+		return rates, nil
+	}
+
+	// the provider rates may be cached and shared, they are copied before the currency rates are set
+	current := &domain.ExchangeRates{Date: rates.Date, Rates: make(map[string]float64, len(rates.Rates)+len(currencyRates))}
+	for currency, rate := range rates.Rates {
+		current.Rates[currency] = rate
+	}
+	for _, currencyRate := range currencyRates {
+		current.Rates[currencyRate.Currency] = currencyRate.USDRate
+	}
+	return current, nil
+}
+
+func (es ExchangeRateService) GetCurrencyRates() ([]*domain.CurrencyRate, error) {
+	rates, err := es.exchangeRateRepository.GetCurrencyRates()
+	if err != nil {
+		es.logger.Error(err)
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (es ExchangeRateService) CreateCurrencyRate(rateRequest *request.CurrencyRateRequest, user string) (*domain.CurrencyRate, error) {
+	rate, err := currencyRate(rateRequest.Currency, rateRequest.USDRate, user)
+	if err != nil {
+		return nil, err
+	}
+
+	usdRate := rate.USDRate
+	err = es.recordChange(&domain.CurrencyRateChange{
+		Currency:  rate.Currency,
+		Action:    domain.CurrencyRateCreated,
+		USDRate:   &usdRate,
+		ChangedBy: user,
+		ChangedAt: rate.UpdatedAt,
+	}, func() error {
+		return es.exchangeRateRepository.CreateCurrencyRate(rate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (es ExchangeRateService) UpdateCurrencyRate(currency string, rateRequest *request.CurrencyRateRequest, user string) (*domain.CurrencyRate, error) {
+	rate, err := currencyRate(currency, rateRequest.USDRate, user)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := es.exchangeRateRepository.GetCurrencyRate(rate.Currency)
+	if err != nil {
+		es.logger.Error(err)
+		return nil, err
+	}
+
+	usdRate := rate.USDRate
+	err = es.recordChange(&domain.CurrencyRateChange{
+		Currency:     rate.Currency,
+		Action:       domain.CurrencyRateUpdated,
+		PreviousRate: &previous.USDRate,
+		USDRate:      &usdRate,
+		ChangedBy:    user,
+		ChangedAt:    rate.UpdatedAt,
+	}, func() error {
+		_, err := es.exchangeRateRepository.UpdateCurrencyRate(rate)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (es ExchangeRateService) DeleteCurrencyRate(currency string, user string) error {
+	code, err := domain.NormalizeCurrency(currency)
+	if err != nil {
+		return err
+	}
+	previous, err := es.exchangeRateRepository.GetCurrencyRate(code)
+	if err != nil {
+		es.logger.Error(err)
+		return err
+	}
+
+	return es.recordChange(&domain.CurrencyRateChange{
+		Currency:     code,
+		Action:       domain.CurrencyRateDeleted,
+		PreviousRate: &previous.USDRate,
+		ChangedBy:    user,
+		ChangedAt:    time.Now(),
+	}, func() error {
+		_, err := es.exchangeRateRepository.DeleteCurrencyRate(code)
+		return err
+	})
+}
+
+// GetCurrencyRateChanges returns the changes of the currency, or of every currency when it is empty, newest first
+func (es ExchangeRateService) GetCurrencyRateChanges(currency string) ([]*domain.CurrencyRateChange, error) {
+	if len(strings.TrimSpace(currency)) > 0 {
+		code, err := domain.NormalizeCurrency(currency)
+		if err != nil {
+			return nil, err
+		}
+		currency = code
+	}
+	changes, err := es.exchangeRateRepository.GetCurrencyRateChanges(currency)
+	if err != nil {
+		es.logger.Error(err)
+		return nil, err
+	}
+	return changes, nil
+}
+
+// recordChange records the change before apply makes it, so no currency rate is changed without a record.
+// The record is removed again when apply fails.
+func (es ExchangeRateService) recordChange(change *domain.CurrencyRateChange, apply func() error) error {
+	if err := es.exchangeRateRepository.AddCurrencyRateChange(change); err != nil {
+		es.logger.Error("Error recording the ", change.Action, " ", change.Currency, " rate: ", err)
+		return err
+	}
+	if err := apply(); err != nil {
+		es.logger.Error(err)
+		if err := es.exchangeRateRepository.DeleteCurrencyRateChange(change.Id); err != nil {
+			es.logger.Error("Error removing the record of the failed ", change.Action, " ", change.Currency, " rate: ", err)
+		}
+		return err
+	}
+	es.logger.Info("Currency rate ", change.Currency, " ", change.Action, " by ", change.ChangedBy)
+	return nil
+}
+
+// currencyRate checks the currency and the rate of a change, USD is always worth one USD
+func currencyRate(currency string, usdRate float64, user string) (*domain.CurrencyRate, error) {
+	code, err := domain.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if code == currencyUSD {
+		return nil, domain.NewValidationError("The USD rate can not be changed")
+	}
+	if usdRate <= 0 || math.IsInf(usdRate, 0) || math.IsNaN(usdRate) {
+		return nil, domain.NewValidationError("usdRate must be a positive number")
+	}
+	return &domain.CurrencyRate{Currency: code, USDRate: usdRate, UpdatedBy: user, UpdatedAt: time.Now()}, nil
+}
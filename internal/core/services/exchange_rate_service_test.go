@@ -0,0 +1,432 @@
+package services
+
+import (
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	mock_ports "github.com/inkoba/app_for_HR/internal/core/ports/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+	"time"
+)
+
+func TestExchangeRateService_Import(t *testing.T) {
+	type mockBehavior func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRatesParser)
+
+	days := []*domain.ExchangeRates{
+		{Date: time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC), Rates: map[string]float64{"EUR": 1.1884, "PLN": 0.262914}},
+		{Date: time.Date(2021, 6, 29, 0, 0, 0, 0, time.UTC), Rates: map[string]float64{"EUR": 1.1894, "PLN": 0.263118}},
+	}
+
+	testTable := []struct {
+		name          string
+		ratesImport   *request.ExchangeRatesImport
+		mockBehavior  mockBehavior
+		expected      *response.ExchangeRatesImportReport
+		expectedError bool
+	}{
+		{
+			name:        "every day of the file is stored",
+			ratesImport: &request.ExchangeRatesImport{FileName: "eurofxref-hist.csv"},
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRatesParser) {
+				p.EXPECT().Format("", "eurofxref-hist.csv").Return(request.RatesFormatCSV, nil)
+				p.EXPECT().Parse(gomock.Any(), request.RatesFormatCSV).Return(days, nil)
+				r.EXPECT().SaveRates(days)
+			},
+			expected: &response.ExchangeRatesImportReport{Days: 2, From: "2021-06-29", To: "2021-06-30", Currencies: []string{"EUR", "PLN", "USD"}},
+		},
+		{
+			name:        "get error when the format is unknown",
+			ratesImport: &request.ExchangeRatesImport{Format: "json"},
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRatesParser) {
+				p.EXPECT().Format("json", "").Return("", domain.NewValidationError("Exchange rates format %q is not supported", "json"))
+			},
+			expectedError: true,
+		},
+		{
+			name:        "get error when the file can not be parsed",
+			ratesImport: &request.ExchangeRatesImport{FileName: "rates.csv"},
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRatesParser) {
+				p.EXPECT().Format("", "rates.csv").Return(request.RatesFormatCSV, nil)
+				p.EXPECT().Parse(gomock.Any(), request.RatesFormatCSV).Return(nil, domain.NewValidationError("The exchange rates file has no USD rate"))
+			},
+			expectedError: true,
+		},
+		{
+			name:        "get error when the database is unavailable",
+			ratesImport: &request.ExchangeRatesImport{FileName: "rates.csv"},
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRatesParser) {
+				p.EXPECT().Format("", "rates.csv").Return(request.RatesFormatCSV, nil)
+				p.EXPECT().Parse(gomock.Any(), request.RatesFormatCSV).Return(days, nil)
+				r.EXPECT().SaveRates(gomock.Any()).Return(errors.New("database is unavailable"))
+			},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockIExchangeRateRepository(c)
+			parser := mock_ports.NewMockIExchangeRatesParser(c)
+			testCase.mockBehavior(repo, parser)
+			service := NewExchangeRateService(repo, mock_ports.NewMockIExchangeRateProvider(c), parser, logrus.New())
+
+			wantResult, err := service.Import(testCase.ratesImport)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+		})
+	}
+}
+
+func TestExchangeRateService_GetRates(t *testing.T) {
+	type mockBehavior func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRateProvider)
+
+	date := time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)
+	stored := &domain.ExchangeRates{Date: date.AddDate(0, 0, -1), Rates: map[string]float64{"EUR": 1.19}}
+	current := &domain.ExchangeRates{Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Rates: map[string]float64{"EUR": 1.09}}
+
+	testTable := []struct {
+		name          string
+		date          *time.Time
+		mockBehavior  mockBehavior
+		expected      *domain.ExchangeRates
+		expectedError bool
+	}{
+		{
+			name: "current rates are returned without a date",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRateProvider) {
+				p.EXPECT().GetRates().Return(current, nil)
+				r.EXPECT().GetCurrencyRates()
+			},
+			expected: current,
+		},
+		{
+			name: "currency rates set by admins are used over the current rates",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRateProvider) {
+				p.EXPECT().GetRates().Return(current, nil)
+				r.EXPECT().GetCurrencyRates().Return([]*domain.CurrencyRate{{Currency: "EUR", USDRate: 1.1}, {Currency: "PLN", USDRate: 0.25}}, nil)
+			},
+			expected: &domain.ExchangeRates{Date: current.Date, Rates: map[string]float64{"EUR": 1.1, "PLN": 0.25}},
+		},
+		{
+			name: "stored rates of the date are returned",
+			date: &date,
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRateProvider) {
+				r.EXPECT().GetRates(date).Return(stored, nil)
+			},
+			expected: stored,
+		},
+		{
+			name: "current rates are returned when no rates are stored for the date",
+			date: &date,
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRateProvider) {
+				r.EXPECT().GetRates(date).Return(nil, domain.ErrExchangeRatesNotFound)
+				p.EXPECT().GetRates().Return(current, nil)
+				r.EXPECT().GetCurrencyRates()
+			},
+			expected: current,
+		},
+		{
+			name: "get error when the database is unavailable",
+			date: &date,
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRateProvider) {
+				r.EXPECT().GetRates(date).Return(nil, errors.New("database is unavailable"))
+			},
+			expectedError: true,
+		},
+		{
+			name: "get error when the current rates are unavailable",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRateProvider) {
+				p.EXPECT().GetRates().Return(nil, errors.New("rates are unavailable"))
+			},
+			expectedError: true,
+		},
+		{
+			name: "get error when the currency rates are unavailable",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository, p *mock_ports.MockIExchangeRateProvider) {
+				p.EXPECT().GetRates().Return(current, nil)
+				r.EXPECT().GetCurrencyRates().Return(nil, errors.New("database is unavailable"))
+			},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockIExchangeRateRepository(c)
+			provider := mock_ports.NewMockIExchangeRateProvider(c)
+			testCase.mockBehavior(repo, provider)
+			service := NewExchangeRateService(repo, provider, mock_ports.NewMockIExchangeRatesParser(c), logrus.New())
+
+			wantResult, err := service.GetRates(testCase.date)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+		})
+	}
+}
+
+func TestExchangeRateService_CreateCurrencyRate(t *testing.T) {
+	type mockBehavior func(r *mock_ports.MockIExchangeRateRepository)
+
+	changeId := primitive.NewObjectID()
+
+	testTable := []struct {
+		name          string
+		rateRequest   *request.CurrencyRateRequest
+		mockBehavior  mockBehavior
+		expected      string
+		expectedError error
+	}{
+		{
+			name:        "change is recorded before the rate is created",
+			rateRequest: &request.CurrencyRateRequest{Currency: "zł", USDRate: 0.25},
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository) {
+				gomock.InOrder(
+					r.EXPECT().AddCurrencyRateChange(gomock.Any()).Do(func(change *domain.CurrencyRateChange) {
+						assert.Equal(t, "PLN", change.Currency)
+						assert.Equal(t, domain.CurrencyRateCreated, change.Action)
+						assert.Equal(t, "admin", change.ChangedBy)
+						assert.Nil(t, change.PreviousRate)
+						assert.Equal(t, 0.25, *change.USDRate)
+					}),
+					r.EXPECT().CreateCurrencyRate(gomock.Any()),
+				)
+			},
+			expected: "PLN",
+		},
+		{
+			name:          "get error when the currency is unknown",
+			rateRequest:   &request.CurrencyRateRequest{Currency: "ABC", USDRate: 0.25},
+			mockBehavior:  func(r *mock_ports.MockIExchangeRateRepository) {},
+			expectedError: &domain.UnknownCurrencyError{Currency: "ABC"},
+		},
+		{
+			name:          "get error when the currency is USD",
+			rateRequest:   &request.CurrencyRateRequest{Currency: "USD", USDRate: 2},
+			mockBehavior:  func(r *mock_ports.MockIExchangeRateRepository) {},
+			expectedError: domain.NewValidationError("The USD rate can not be changed"),
+		},
+		{
+			name:          "get error when the rate is not positive",
+			rateRequest:   &request.CurrencyRateRequest{Currency: "PLN"},
+			mockBehavior:  func(r *mock_ports.MockIExchangeRateRepository) {},
+			expectedError: domain.NewValidationError("usdRate must be a positive number"),
+		},
+		{
+			name:        "record is removed when the currency already has a rate",
+			rateRequest: &request.CurrencyRateRequest{Currency: "PLN", USDRate: 0.25},
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository) {
+				gomock.InOrder(
+					r.EXPECT().AddCurrencyRateChange(gomock.Any()).Do(func(change *domain.CurrencyRateChange) {
+						change.Id = changeId
+					}),
+					r.EXPECT().CreateCurrencyRate(gomock.Any()).Return(domain.ErrCurrencyRateExists),
+					r.EXPECT().DeleteCurrencyRateChange(changeId),
+				)
+			},
+			expectedError: domain.ErrCurrencyRateExists,
+		},
+		{
+			name:        "rate is not created when the change can not be recorded",
+			rateRequest: &request.CurrencyRateRequest{Currency: "PLN", USDRate: 0.25},
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository) {
+				r.EXPECT().AddCurrencyRateChange(gomock.Any()).Return(errors.New("database is unavailable"))
+			},
+			expectedError: errors.New("database is unavailable"),
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockIExchangeRateRepository(c)
+			testCase.mockBehavior(repo)
+			service := NewExchangeRateService(repo, mock_ports.NewMockIExchangeRateProvider(c), mock_ports.NewMockIExchangeRatesParser(c), logrus.New())
+
+			wantResult, err := service.CreateCurrencyRate(testCase.rateRequest, "admin")
+
+			if testCase.expectedError != nil {
+				assert.Equal(t, testCase.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult.Currency)
+				assert.Equal(t, "admin", wantResult.UpdatedBy)
+			}
+		})
+	}
+}
+
+func TestExchangeRateService_UpdateCurrencyRate(t *testing.T) {
+	type mockBehavior func(r *mock_ports.MockIExchangeRateRepository)
+
+	changeId := primitive.NewObjectID()
+	previous := &domain.CurrencyRate{Currency: "PLN", USDRate: 0.24}
+
+	testTable := []struct {
+		name          string
+		currency      string
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name:     "change with the previous rate is recorded before the rate is replaced",
+			currency: "pln",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository) {
+				gomock.InOrder(
+					r.EXPECT().GetCurrencyRate("PLN").Return(previous, nil),
+					r.EXPECT().AddCurrencyRateChange(gomock.Any()).Do(func(change *domain.CurrencyRateChange) {
+						assert.Equal(t, domain.CurrencyRateUpdated, change.Action)
+						assert.Equal(t, 0.24, *change.PreviousRate)
+						assert.Equal(t, 0.26, *change.USDRate)
+					}),
+					r.EXPECT().UpdateCurrencyRate(gomock.Any()).Return(previous, nil),
+				)
+			},
+		},
+		{
+			name:     "get error when the currency has no rate",
+			currency: "PLN",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository) {
+				r.EXPECT().GetCurrencyRate("PLN").Return(nil, domain.ErrCurrencyRateNotFound)
+			},
+			expectedError: domain.ErrCurrencyRateNotFound,
+		},
+		{
+			name:     "rate is not replaced when the change can not be recorded",
+			currency: "PLN",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository) {
+				r.EXPECT().GetCurrencyRate("PLN").Return(previous, nil)
+				r.EXPECT().AddCurrencyRateChange(gomock.Any()).Return(errors.New("database is unavailable"))
+			},
+			expectedError: errors.New("database is unavailable"),
+		},
+		{
+			name:     "record is removed when the rate can not be replaced",
+			currency: "PLN",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository) {
+				gomock.InOrder(
+					r.EXPECT().GetCurrencyRate("PLN").Return(previous, nil),
+					r.EXPECT().AddCurrencyRateChange(gomock.Any()).Do(func(change *domain.CurrencyRateChange) {
+						change.Id = changeId
+					}),
+					r.EXPECT().UpdateCurrencyRate(gomock.Any()).Return(nil, domain.ErrCurrencyRateNotFound),
+					r.EXPECT().DeleteCurrencyRateChange(changeId),
+				)
+			},
+			expectedError: domain.ErrCurrencyRateNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockIExchangeRateRepository(c)
+			testCase.mockBehavior(repo)
+			service := NewExchangeRateService(repo, mock_ports.NewMockIExchangeRateProvider(c), mock_ports.NewMockIExchangeRatesParser(c), logrus.New())
+
+			wantResult, err := service.UpdateCurrencyRate(testCase.currency, &request.CurrencyRateRequest{USDRate: 0.26}, "admin")
+
+			if testCase.expectedError != nil {
+				assert.Equal(t, testCase.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "PLN", wantResult.Currency)
+				assert.Equal(t, 0.26, wantResult.USDRate)
+			}
+		})
+	}
+}
+
+func TestExchangeRateService_DeleteCurrencyRate(t *testing.T) {
+	type mockBehavior func(r *mock_ports.MockIExchangeRateRepository)
+
+	changeId := primitive.NewObjectID()
+	previous := &domain.CurrencyRate{Currency: "PLN", USDRate: 0.24}
+
+	testTable := []struct {
+		name          string
+		currency      string
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name:     "change is recorded before the rate is deleted",
+			currency: "PLN",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository) {
+				gomock.InOrder(
+					r.EXPECT().GetCurrencyRate("PLN").Return(previous, nil),
+					r.EXPECT().AddCurrencyRateChange(gomock.Any()).Do(func(change *domain.CurrencyRateChange) {
+						assert.Equal(t, domain.CurrencyRateDeleted, change.Action)
+						assert.Equal(t, 0.24, *change.PreviousRate)
+						assert.Nil(t, change.USDRate)
+						assert.Equal(t, "admin", change.ChangedBy)
+					}),
+					r.EXPECT().DeleteCurrencyRate("PLN").Return(previous, nil),
+				)
+			},
+		},
+		{
+			name:     "get error when the currency has no rate",
+			currency: "PLN",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository) {
+				r.EXPECT().GetCurrencyRate("PLN").Return(nil, domain.ErrCurrencyRateNotFound)
+			},
+			expectedError: domain.ErrCurrencyRateNotFound,
+		},
+		{
+			name:     "record is removed when the rate can not be deleted",
+			currency: "PLN",
+			mockBehavior: func(r *mock_ports.MockIExchangeRateRepository) {
+				gomock.InOrder(
+					r.EXPECT().GetCurrencyRate("PLN").Return(previous, nil),
+					r.EXPECT().AddCurrencyRateChange(gomock.Any()).Do(func(change *domain.CurrencyRateChange) {
+						change.Id = changeId
+					}),
+					r.EXPECT().DeleteCurrencyRate("PLN").Return(nil, errors.New("database is unavailable")),
+					r.EXPECT().DeleteCurrencyRateChange(changeId),
+				)
+			},
+			expectedError: errors.New("database is unavailable"),
+		},
+		{
+			name:          "get error when the currency is unknown",
+			currency:      "ABC",
+			mockBehavior:  func(r *mock_ports.MockIExchangeRateRepository) {},
+			expectedError: &domain.UnknownCurrencyError{Currency: "ABC"},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockIExchangeRateRepository(c)
+			testCase.mockBehavior(repo)
+			service := NewExchangeRateService(repo, mock_ports.NewMockIExchangeRateProvider(c), mock_ports.NewMockIExchangeRatesParser(c), logrus.New())
+
+			err := service.DeleteCurrencyRate(testCase.currency, "admin")
+
+			assert.Equal(t, testCase.expectedError, err)
+		})
+	}
+}
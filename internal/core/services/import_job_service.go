@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"github.com/inkoba/app_for_HR/internal/config"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultJobRetention = 24 * time.Hour
+
+type importJob struct {
+	job    domain.ImportJob
+	cancel context.CancelFunc
+}
+
+// ImportJobService runs salary uploads in the background one at a time and keeps their progress in memory
+type ImportJobService struct {
+	salaryService ports.ISalaryService
+	tempDir       string
+	retention     time.Duration
+	logger        *logrus.Logger
+	mutex         *sync.Mutex
+	running       *sync.Mutex
+	jobs          map[string]*importJob
+}
+
+var _ ports.IImportJobService = (*ImportJobService)(nil)
+
+func NewImportJobService(importConfig config.ImportConfig, salaryService ports.ISalaryService, logger *logrus.Logger) *ImportJobService {
+	retention := importConfig.JobRetention
+	if retention <= 0 {
+		retention = defaultJobRetention
+	}
+	return &ImportJobService{
+		salaryService,
+		importConfig.TempDir,
+		retention,
+		logger,
+		&sync.Mutex{},
+		&sync.Mutex{},
+		map[string]*importJob{},
+	}
+}
+
+// Start saves the uploaded file to a temporary file and queues its import
+func (js ImportJobService) Start(upload *request.SalaryUpload) (*domain.ImportJob, error) {
+	if err := validateUploadMode(upload); err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp(js.tempDir, "salaries-*.csv")
+	if err != nil {
+		js.logger.Error(err)
+		return nil, err
+	}
+
+	if _, err := io.Copy(file, upload.File); err != nil {
+		js.logger.Error(err)
+		removeTempFile(file, js.logger)
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		js.logger.Error(err)
+		removeTempFile(file, js.logger)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	entry := &importJob{
+		job: domain.ImportJob{
+			Id:        primitive.NewObjectID().Hex(),
+			Status:    domain.ImportJobStatusQueued,
+			FileName:  upload.FileName,
+			Uploader:  upload.Uploader,
+			Mode:      upload.Mode,
+			CreatedAt: time.Now(),
+		},
+		cancel: cancel,
+	}
+
+	js.mutex.Lock()
+	js.deleteExpiredJobs()
+	js.jobs[entry.job.Id] = entry
+	job := entry.job
+	js.mutex.Unlock()
+
+	go js.run(ctx, entry, file, *upload)
+
+	return &job, nil
+}
+
+func (js ImportJobService) Get(id string) (*domain.ImportJob, error) {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+
+	entry, ok := js.jobs[id]
+	if !ok {
+		return nil, domain.ErrImportJobNotFound
+	}
+	return snapshot(entry), nil
+}
+
+// Cancel stops the import, the staging dataset of a cancelled job is discarded by the salary service
+func (js ImportJobService) Cancel(id string) (*domain.ImportJob, error) {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+
+	entry, ok := js.jobs[id]
+	if !ok {
+		return nil, domain.ErrImportJobNotFound
+	}
+	if entry.job.IsFinished() {
+		return nil, domain.ErrImportJobFinished
+	}
+
+	entry.cancel()
+	return snapshot(entry), nil
+}
+
+func (js ImportJobService) run(ctx context.Context, entry *importJob, file *os.File, upload request.SalaryUpload) {
+	defer removeTempFile(file, js.logger)
+	defer entry.cancel()
+
+	js.running.Lock()
+	defer js.running.Unlock()
+
+	startedAt := time.Now()
+	js.update(entry, func(job *domain.ImportJob) {
+		job.Status = domain.ImportJobStatusRunning
+		job.StartedAt = &startedAt
+	})
+
+	upload.File = file
+	upload.Progress = func(report *response.SalaryUploadReport) {
+		js.update(entry, func(job *domain.ImportJob) {
+			job.RowsProcessed = report.TotalRecords
+			job.RowsSkipped = report.SkippedRecords
+			job.Errors = append(job.Errors, report.Errors[len(job.Errors):]...)
+		})
+	}
+
+	var report *response.SalaryUploadReport
+	err := ctx.Err()
+	if err == nil {
+		report, err = js.salaryService.Create(ctx, &upload)
+	}
+
+	finishedAt := time.Now()
+	js.update(entry, func(job *domain.ImportJob) {
+		job.FinishedAt = &finishedAt
+		switch {
+		case err == nil:
+			job.Status = domain.ImportJobStatusSucceeded
+			job.Result = report
+		case ctx.Err() != nil:
+			job.Status = domain.ImportJobStatusCancelled
+		default:
+			job.Status = domain.ImportJobStatusFailed
+			job.Error = err.Error()
+		}
+	})
+	js.logger.Info("Import job ", entry.job.Id, " finished")
+}
+
+func (js ImportJobService) update(entry *importJob, change func(job *domain.ImportJob)) {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	change(&entry.job)
+}
+
+func (js ImportJobService) deleteExpiredJobs() {
+	for id, entry := range js.jobs {
+		if entry.job.IsFinished() && time.Since(*entry.job.FinishedAt) > js.retention {
+			delete(js.jobs, id)
+		}
+	}
+}
+
+func snapshot(entry *importJob) *domain.ImportJob {
+	job := entry.job
+	job.Errors = append([]*response.RowError(nil), entry.job.Errors...)
+	return &job
+}
+
+func removeTempFile(file *os.File, logger *logrus.Logger) {
+	if err := file.Close(); err != nil {
+		logger.Error(err)
+	}
+	if err := os.Remove(file.Name()); err != nil {
+		logger.Error(err)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/inkoba/app_for_HR/internal/config"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	mock_ports "github.com/inkoba/app_for_HR/internal/core/ports/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testRowErrors = []*response.RowError{
+	{Line: 3, Column: domain.ColumnSalary, Code: response.RowErrorMissingValue, Message: "The salary field is empty"},
+}
+
+func waitForImportJob(t *testing.T, service *ImportJobService, id string) *domain.ImportJob {
+	var job *domain.ImportJob
+	assert.Eventually(t, func() bool {
+		var err error
+		job, err = service.Get(id)
+		return err == nil && job.IsFinished()
+	}, time.Second, time.Millisecond)
+	return job
+}
+
+func TestImportJobService_Start(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryService)
+
+	testTable := []struct {
+		name           string
+		mode           string
+		mockBehavior   mockBehavior
+		expectedStatus string
+		expectedJob    func(job *domain.ImportJob)
+	}{
+		{
+			name: "job succeeds with the upload report",
+			mockBehavior: func(s *mock_ports.MockISalaryService) {
+				s.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, upload *request.SalaryUpload) (*response.SalaryUploadReport, error) {
+						content, err := io.ReadAll(upload.File)
+						assert.NoError(t, err)
+						assert.Equal(t, testSalaryHeader, string(content))
+						report := &response.SalaryUploadReport{TotalRecords: 3, SkippedRecords: 1, Errors: testRowErrors}
+						upload.Progress(report)
+						report.Version = "3d624904890861643c610064"
+						return report, nil
+					})
+			},
+			expectedJob: func(job *domain.ImportJob) {
+				assert.Equal(t, domain.ImportJobStatusSucceeded, job.Status)
+				assert.Equal(t, request.UploadModeReplace, job.Mode)
+				assert.Equal(t, 3, job.RowsProcessed)
+				assert.Equal(t, 1, job.RowsSkipped)
+				assert.Equal(t, testRowErrors, job.Errors)
+				assert.Equal(t, "3d624904890861643c610064", job.Result.Version)
+				assert.NotNil(t, job.FinishedAt)
+			},
+		},
+		{
+			name: "job fails with the import error",
+			mode: request.UploadModeAppend,
+			mockBehavior: func(s *mock_ports.MockISalaryService) {
+				s.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, errors.New("database is unavailable"))
+			},
+			expectedJob: func(job *domain.ImportJob) {
+				assert.Equal(t, domain.ImportJobStatusFailed, job.Status)
+				assert.Equal(t, request.UploadModeAppend, job.Mode)
+				assert.Equal(t, "database is unavailable", job.Error)
+				assert.Nil(t, job.Result)
+			},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			salaryService := mock_ports.NewMockISalaryService(c)
+			testCase.mockBehavior(salaryService)
+			service := NewImportJobService(config.ImportConfig{TempDir: t.TempDir()}, salaryService, logrus.New())
+
+			job, err := service.Start(&request.SalaryUpload{File: strings.NewReader(testSalaryHeader), FileName: "salaries.csv", Mode: testCase.mode})
+			assert.NoError(t, err)
+
+			testCase.expectedJob(waitForImportJob(t, service, job.Id))
+		})
+	}
+}
+
+func TestImportJobService_StartWithUnsupportedMode(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	service := NewImportJobService(config.ImportConfig{TempDir: t.TempDir()}, mock_ports.NewMockISalaryService(c), logrus.New())
+
+	_, err := service.Start(&request.SalaryUpload{File: strings.NewReader(testSalaryHeader), Mode: "merge"})
+
+	var validationError *domain.ValidationError
+	assert.True(t, errors.As(err, &validationError))
+}
+
+func TestImportJobService_Cancel(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	started := make(chan struct{})
+	salaryService := mock_ports.NewMockISalaryService(c)
+	salaryService.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, upload *request.SalaryUpload) (*response.SalaryUploadReport, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	service := NewImportJobService(config.ImportConfig{TempDir: t.TempDir()}, salaryService, logrus.New())
+
+	job, err := service.Start(&request.SalaryUpload{File: strings.NewReader(testSalaryHeader)})
+	assert.NoError(t, err)
+	<-started
+
+	_, err = service.Cancel(job.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ImportJobStatusCancelled, waitForImportJob(t, service, job.Id).Status)
+
+	_, err = service.Cancel(job.Id)
+	assert.Equal(t, domain.ErrImportJobFinished, err)
+
+	_, err = service.Cancel("3d624904890861643c610064")
+	assert.Equal(t, domain.ErrImportJobNotFound, err)
+}
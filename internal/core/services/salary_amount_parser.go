@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const thousand = 1000
+
+var (
+	errSalaryAmountMissing  = errors.New("The salary has no amount")
+	errSalaryAmountInvalid  = errors.New("The salary amount is not a number")
+	errSalaryCurrency       = errors.New("The salary has no currency")
+	errSalaryCurrencies     = errors.New("The salary has more than one currency")
+	errSalaryRangeReversed  = errors.New("The salary range starts with the bigger amount")
+	errSalaryRangeMalformed = errors.New("The salary range must have two amounts")
+)
+
+// salaryAmount is a salary answer of the survey, a single amount has the same Min and Max
+type salaryAmount struct {
+	Min      float64
+	Max      float64
+	Currency string
+}
+
+func (sa salaryAmount) Mean() float64 {
+	return (sa.Min + sa.Max) / 2
+}
+
+// parseSalaryAmount reads salary answers like "1000 USD", "$1,500", "1 500 EUR", "1500EUR", "1.5k USD",
+// "2000-2500 USD" or "₽120000". The currency may be a symbol, an ISO 4217 code or an alias before or after the amount,
+// a currency that is not known returns a *domain.UnknownCurrencyError.
+func parseSalaryAmount(value string) (*salaryAmount, error) {
+	var currencies []string
+	var unknownCurrency error
+	var amount strings.Builder
+	var code strings.Builder
+
+	addCurrency := func(value string) {
+		currency, err := domain.NormalizeCurrency(value)
+		if err != nil {
+			unknownCurrency = err
+			return
+		}
+		currencies = append(currencies, currency)
+	}
+	flushCode := func() {
+		if code.Len() == 0 {
+			return
+		}
+		if word := code.String(); strings.EqualFold(word, "k") {
+			amount.WriteString("k")
+		} else {
+			addCurrency(word)
+		}
+		code.Reset()
+	}
+
+	for _, r := range value {
+		if unicode.Is(unicode.Sc, r) {
+			flushCode()
+			addCurrency(string(r))
+			continue
+		}
+		if unicode.IsLetter(r) {
+			code.WriteRune(r)
+			continue
+		}
+		flushCode()
+		if !unicode.IsSpace(r) {
+			amount.WriteRune(r)
+		}
+	}
+	flushCode()
+
+	if unknownCurrency != nil {
+		return nil, unknownCurrency
+	}
+	currency, err := singleCurrency(currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds, err := parseAmountRange(amount.String())
+	if err != nil {
+		return nil, err
+	}
+	return &salaryAmount{Min: bounds[0], Max: bounds[1], Currency: currency}, nil
+}
+
+func singleCurrency(currencies []string) (string, error) {
+	if len(currencies) == 0 {
+		return "", errSalaryCurrency
+	}
+	for _, currency := range currencies[1:] {
+		if currency != currencies[0] {
+			return "", errSalaryCurrencies
+		}
+	}
+	return currencies[0], nil
+}
+
+// parseAmountRange reads "1500" or "2000-2500" with spaces already removed.
+// A "k" suffix on the upper bound only also applies to a lower bound written in thousands, like "2-3k".
+func parseAmountRange(value string) ([2]float64, error) {
+	if len(value) == 0 {
+		return [2]float64{}, errSalaryAmountMissing
+	}
+
+	isDash := func(r rune) bool {
+		return r == '-' || r == '–' || r == '—'
+	}
+	dashes := 0
+	for _, r := range value {
+		if isDash(r) {
+			dashes++
+		}
+	}
+	parts := strings.FieldsFunc(value, isDash)
+	if dashes > 1 || len(parts) != dashes+1 {
+		return [2]float64{}, errSalaryRangeMalformed
+	}
+
+	var bounds [2]float64
+	thousands := make([]bool, len(parts))
+	for i, part := range parts {
+		number, inThousands, err := parseAmount(part)
+		if err != nil {
+			return [2]float64{}, err
+		}
+		bounds[i] = number
+		thousands[i] = inThousands
+	}
+
+	if len(parts) == 1 {
+		bounds[1] = bounds[0]
+		return bounds, nil
+	}
+	if thousands[1] && !thousands[0] && bounds[0]*thousand <= bounds[1] {
+		bounds[0] *= thousand
+	}
+	if bounds[0] > bounds[1] {
+		return [2]float64{}, errSalaryRangeReversed
+	}
+	return bounds, nil
+}
+
+// parseAmount reads a number with optional thousands separators and "k" suffix
+func parseAmount(value string) (float64, bool, error) {
+	inThousands := strings.HasSuffix(value, "k")
+	value = strings.TrimSuffix(value, "k")
+	if len(value) == 0 {
+		return 0, false, errSalaryAmountMissing
+	}
+
+	number, err := strconv.ParseFloat(normalizeSeparators(value, inThousands), bitSize)
+	if err != nil || number < 0 {
+		return 0, false, errSalaryAmountInvalid
+	}
+	if inThousands {
+		number *= thousand
+	}
+	return number, inThousands, nil
+}
+
+// normalizeSeparators turns "1,500", "1.500,50" or "1,5" into a number strconv can parse.
+// When both separators are used the last one is decimal. A single separator followed by exactly
+// three digits is a thousands separator unless the amount is in thousands already.
+func normalizeSeparators(value string, inThousands bool) string {
+	lastComma := strings.LastIndex(value, ",")
+	lastDot := strings.LastIndex(value, ".")
+
+	switch {
+	case lastComma >= 0 && lastDot >= 0:
+		if lastComma > lastDot {
+			return strings.Replace(strings.ReplaceAll(value, ".", ""), ",", ".", 1)
+		}
+		return strings.ReplaceAll(value, ",", "")
+	case lastComma >= 0 || lastDot >= 0:
+		separator := ","
+		if lastDot >= 0 {
+			separator = "."
+		}
+		last := strings.LastIndex(value, separator)
+		if strings.Count(value, separator) > 1 || (len(value)-last-1 == 3 && !inThousands) {
+			return strings.ReplaceAll(value, separator, "")
+		}
+		return strings.Replace(value, separator, ".", 1)
+	}
+	return value
+}
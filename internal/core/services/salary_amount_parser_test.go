@@ -0,0 +1,184 @@
+package services
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseSalaryAmount(t *testing.T) {
+	testTable := []struct {
+		name          string
+		value         string
+		expected      *salaryAmount
+		expectedError error
+	}{
+		{
+			name:     "amount followed by a currency code",
+			value:    "1000 USD",
+			expected: &salaryAmount{Min: 1000, Max: 1000, Currency: "USD"},
+		},
+		{
+			name:     "currency symbol with a comma thousands separator",
+			value:    "$1,500",
+			expected: &salaryAmount{Min: 1500, Max: 1500, Currency: "USD"},
+		},
+		{
+			name:     "space thousands separator",
+			value:    "1 500 EUR",
+			expected: &salaryAmount{Min: 1500, Max: 1500, Currency: "EUR"},
+		},
+		{
+			name:     "non-breaking space thousands separator",
+			value:    "1\u00a0500 EUR",
+			expected: &salaryAmount{Min: 1500, Max: 1500, Currency: "EUR"},
+		},
+		{
+			name:     "currency code without a space",
+			value:    "1500EUR",
+			expected: &salaryAmount{Min: 1500, Max: 1500, Currency: "EUR"},
+		},
+		{
+			name:     "currency code before the amount",
+			value:    "USD 2000",
+			expected: &salaryAmount{Min: 2000, Max: 2000, Currency: "USD"},
+		},
+		{
+			name:     "lowercase currency code",
+			value:    "2000 usd",
+			expected: &salaryAmount{Min: 2000, Max: 2000, Currency: "USD"},
+		},
+		{
+			name:     "thousands suffix",
+			value:    "1.5k USD",
+			expected: &salaryAmount{Min: 1500, Max: 1500, Currency: "USD"},
+		},
+		{
+			name:     "uppercase thousands suffix with a space",
+			value:    "2 K EUR",
+			expected: &salaryAmount{Min: 2000, Max: 2000, Currency: "EUR"},
+		},
+		{
+			name:     "range",
+			value:    "2000-2500 USD",
+			expected: &salaryAmount{Min: 2000, Max: 2500, Currency: "USD"},
+		},
+		{
+			name:     "range with spaces and an en dash",
+			value:    "2000 – 2500 USD",
+			expected: &salaryAmount{Min: 2000, Max: 2500, Currency: "USD"},
+		},
+		{
+			name:     "range with the thousands suffix on the upper bound only",
+			value:    "2-3k USD",
+			expected: &salaryAmount{Min: 2000, Max: 3000, Currency: "USD"},
+		},
+		{
+			name:     "range with the thousands suffix on both bounds",
+			value:    "$2k-3.5k",
+			expected: &salaryAmount{Min: 2000, Max: 3500, Currency: "USD"},
+		},
+		{
+			name:     "rouble symbol",
+			value:    "₽120000",
+			expected: &salaryAmount{Min: 120000, Max: 120000, Currency: "RUB"},
+		},
+		{
+			name:     "survey rouble code is turned into the ISO code",
+			value:    "120000 RUS",
+			expected: &salaryAmount{Min: 120000, Max: 120000, Currency: "RUB"},
+		},
+		{
+			name:     "euro symbol after the amount",
+			value:    "3000€",
+			expected: &salaryAmount{Min: 3000, Max: 3000, Currency: "EUR"},
+		},
+		{
+			name:     "pound symbol",
+			value:    "£2500",
+			expected: &salaryAmount{Min: 2500, Max: 2500, Currency: "GBP"},
+		},
+		{
+			name:     "local name of the zloty",
+			value:    "8000 zł",
+			expected: &salaryAmount{Min: 8000, Max: 8000, Currency: "PLN"},
+		},
+		{
+			name:     "any ISO 4217 code",
+			value:    "60000 UAH",
+			expected: &salaryAmount{Min: 60000, Max: 60000, Currency: "UAH"},
+		},
+		{
+			name:     "decimal comma",
+			value:    "1500,5 EUR",
+			expected: &salaryAmount{Min: 1500.5, Max: 1500.5, Currency: "EUR"},
+		},
+		{
+			name:     "dot thousands separator with a decimal comma",
+			value:    "1.500,50 EUR",
+			expected: &salaryAmount{Min: 1500.5, Max: 1500.5, Currency: "EUR"},
+		},
+		{
+			name:     "comma thousands separators with a decimal dot",
+			value:    "1,234,567.89 USD",
+			expected: &salaryAmount{Min: 1234567.89, Max: 1234567.89, Currency: "USD"},
+		},
+		{
+			name:          "amount without a currency",
+			value:         "1000",
+			expectedError: errSalaryCurrency,
+		},
+		{
+			name:          "currency without an amount",
+			value:         "USD",
+			expectedError: errSalaryAmountMissing,
+		},
+		{
+			name:          "currency that is not an ISO 4217 code",
+			value:         "1000 ABC",
+			expectedError: &domain.UnknownCurrencyError{Currency: "ABC"},
+		},
+		{
+			name:          "unknown currency symbol",
+			value:         "¤1000",
+			expectedError: &domain.UnknownCurrencyError{Currency: "¤"},
+		},
+		{
+			name:          "two different currencies",
+			value:         "$1000 EUR",
+			expectedError: errSalaryCurrencies,
+		},
+		{
+			name:          "amount that is not a number",
+			value:         "1/2 USD",
+			expectedError: errSalaryAmountInvalid,
+		},
+		{
+			name:          "negative amount",
+			value:         "-1000 USD",
+			expectedError: errSalaryRangeMalformed,
+		},
+		{
+			name:          "range with three bounds",
+			value:         "1000-2000-3000 USD",
+			expectedError: errSalaryRangeMalformed,
+		},
+		{
+			name:          "range with the bigger amount first",
+			value:         "2500-2000 USD",
+			expectedError: errSalaryRangeReversed,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := parseSalaryAmount(testCase.value)
+
+			if testCase.expectedError != nil {
+				assert.Equal(t, testCase.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"math"
+	"strings"
+)
+
+const (
+	reasonAmountInvalid    = "the amount is not a valid number"
+	reasonCurrencyUnknown  = "the currency is not an ISO 4217 code"
+	reasonRateMissing      = "there is no exchange rate for the currency"
+	reasonAmountUSDMissing = "the amount has no valid USD value stored at import"
+)
+
+// salaryConverter converts the salaries read from the database to one currency
+type salaryConverter struct {
+	rates    *domain.ExchangeRates
+	currency string
+	// usdRate is the USD value of one unit of currency
+	usdRate float64
+	// usdRates are today's USD values of one unit of every currency, the USD amounts stored at import
+	// are used when they are nil
+	usdRates map[string]float64
+}
+
+// newSalaryConverter returns the converter to the target currency, USD when it is empty,
+// with the rates of the USD amounts selected by the condition
+func (ss SalaryService) newSalaryConverter(targetCurrency string, condition *request.ConditionForFilteringSalaries) (*salaryConverter, error) {
+	rates, err := ss.conditionRates(condition)
+	if err != nil {
+		return nil, err
+	}
+	currency, usdRate := currencyUSD, 1.0
+	if len(strings.TrimSpace(targetCurrency)) > 0 {
+		if currency, usdRate, err = ratesUSDRate(targetCurrency, rates); err != nil {
+			return nil, err
+		}
+	}
+	return &salaryConverter{rates, currency, usdRate, condition.USDRates}, nil
+}
+
+// convert returns the conversion of the salary or a *domain.ConversionError telling why it can not be converted
+func (sc *salaryConverter) convert(salary *domain.Salary) (*domain.SalaryConversion, error) {
+	fail := func(reason string) error {
+		return &domain.ConversionError{
+			ResponseId:       salary.ResponseId,
+			OriginalAmount:   salary.Amount,
+			OriginalCurrency: salary.Currency,
+			Currency:         sc.currency,
+			Reason:           reason,
+		}
+	}
+
+	if !isValidAmount(salary.Amount) {
+		return nil, fail(reasonAmountInvalid)
+	}
+	currency, err := domain.NormalizeCurrency(salary.Currency)
+	if err != nil {
+		return nil, fail(reasonCurrencyUnknown)
+	}
+
+	var amountUSD, usdRate float64
+	if sc.usdRates != nil {
+		var ok bool
+		if usdRate, ok = sc.usdRates[currency]; !ok {
+			return nil, fail(reasonRateMissing)
+		}
+		amountUSD = salary.Amount * usdRate
+	} else {
+		amountUSD = salary.AmountUSD
+		if !isValidAmount(amountUSD) || (amountUSD == 0) != (salary.Amount == 0) {
+			return nil, fail(reasonAmountUSDMissing)
+		}
+		if salary.Amount > 0 {
+			usdRate = amountUSD / salary.Amount
+		} else {
+			// the rate of a zero amount is not stored, it is worth nothing in any currency
+			var ok bool
+			if usdRate, ok = sc.rates.USDRate(currency); !ok {
+				return nil, fail(reasonRateMissing)
+			}
+		}
+	}
+
+	rate := 1.0
+	if currency != sc.currency {
+		rate = roundRate(usdRate / sc.usdRate)
+	}
+	return &domain.SalaryConversion{
+		OriginalAmount:   salary.Amount,
+		OriginalCurrency: currency,
+		Amount:           round2(amountUSD / sc.usdRate),
+		Currency:         sc.currency,
+		Rate:             rate,
+	}, nil
+}
+
+func isValidAmount(amount float64) bool {
+	return amount >= 0 && !math.IsInf(amount, 0) && !math.IsNaN(amount)
+}
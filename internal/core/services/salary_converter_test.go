@@ -0,0 +1,127 @@
+package services
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestSalaryConverter_Convert(t *testing.T) {
+	todayRates := map[string]float64{"USD": 1, "EUR": 1.1328, "PLN": 0.25}
+
+	testTable := []struct {
+		name           string
+		converter      *salaryConverter
+		salary         *domain.Salary
+		expected       *domain.SalaryConversion
+		expectedReason string
+	}{
+		{
+			name:      "USD amount stored at import is converted to the target currency",
+			converter: &salaryConverter{testRates, "EUR", 1.1328, nil},
+			salary:    &domain.Salary{Amount: 10000, Currency: "PLN", AmountUSD: 2500},
+			expected:  &domain.SalaryConversion{OriginalAmount: 10000, OriginalCurrency: "PLN", Amount: 2206.92, Currency: "EUR", Rate: 0.220692},
+		},
+		{
+			name:      "salary in the target currency keeps its amount",
+			converter: &salaryConverter{testRates, "EUR", 1.1328, nil},
+			salary:    &domain.Salary{Amount: 3000, Currency: "EUR", AmountUSD: 3398.4},
+			expected:  &domain.SalaryConversion{OriginalAmount: 3000, OriginalCurrency: "EUR", Amount: 3000, Currency: "EUR", Rate: 1},
+		},
+		{
+			name:      "today's rates are used over the USD amount stored at import",
+			converter: &salaryConverter{testRates, "EUR", 1.1328, todayRates},
+			salary:    &domain.Salary{Amount: 10000, Currency: "PLN", AmountUSD: 2700},
+			expected:  &domain.SalaryConversion{OriginalAmount: 10000, OriginalCurrency: "PLN", Amount: 2206.92, Currency: "EUR", Rate: 0.220692},
+		},
+		{
+			name:      "stored currency alias is turned into the ISO code",
+			converter: &salaryConverter{testRates, "USD", 1, nil},
+			salary:    &domain.Salary{Amount: 100000, Currency: "RUS", AmountUSD: 1400},
+			expected:  &domain.SalaryConversion{OriginalAmount: 100000, OriginalCurrency: "RUB", Amount: 1400, Currency: "USD", Rate: 0.014},
+		},
+		{
+			name:      "rate of a zero amount is the current rate",
+			converter: &salaryConverter{testRates, "EUR", 1.1328, nil},
+			salary:    &domain.Salary{Amount: 0, Currency: "PLN", AmountUSD: 0},
+			expected:  &domain.SalaryConversion{OriginalAmount: 0, OriginalCurrency: "PLN", Amount: 0, Currency: "EUR", Rate: 0.220692},
+		},
+		{
+			name:           "get error when the amount is negative",
+			converter:      &salaryConverter{testRates, "USD", 1, nil},
+			salary:         &domain.Salary{Amount: -1000, Currency: "USD", AmountUSD: -1000},
+			expectedReason: reasonAmountInvalid,
+		},
+		{
+			name:           "get error when the amount is not a number",
+			converter:      &salaryConverter{testRates, "USD", 1, nil},
+			salary:         &domain.Salary{Amount: math.NaN(), Currency: "USD"},
+			expectedReason: reasonAmountInvalid,
+		},
+		{
+			name:           "get error when the amount is infinite",
+			converter:      &salaryConverter{testRates, "USD", 1, todayRates},
+			salary:         &domain.Salary{Amount: math.Inf(1), Currency: "USD"},
+			expectedReason: reasonAmountInvalid,
+		},
+		{
+			name:           "get error when the currency is unknown",
+			converter:      &salaryConverter{testRates, "USD", 1, nil},
+			salary:         &domain.Salary{Amount: 1000, Currency: "ABC", AmountUSD: 1000},
+			expectedReason: reasonCurrencyUnknown,
+		},
+		{
+			name:           "get error when the currency is missing",
+			converter:      &salaryConverter{testRates, "USD", 1, nil},
+			salary:         &domain.Salary{Amount: 1000, AmountUSD: 1000},
+			expectedReason: reasonCurrencyUnknown,
+		},
+		{
+			name:           "get error when today's rates have no rate for the currency",
+			converter:      &salaryConverter{testRates, "USD", 1, todayRates},
+			salary:         &domain.Salary{Amount: 2000, Currency: "GBP", AmountUSD: 2540},
+			expectedReason: reasonRateMissing,
+		},
+		{
+			name:           "get error when no USD amount was stored at import",
+			converter:      &salaryConverter{testRates, "USD", 1, nil},
+			salary:         &domain.Salary{Amount: 2000, Currency: "GBP"},
+			expectedReason: reasonAmountUSDMissing,
+		},
+		{
+			name:           "get error when the stored USD amount is not a number",
+			converter:      &salaryConverter{testRates, "EUR", 1.1328, nil},
+			salary:         &domain.Salary{Amount: 2000, Currency: "PLN", AmountUSD: math.NaN()},
+			expectedReason: reasonAmountUSDMissing,
+		},
+		{
+			name:           "get error when a zero amount has no current rate",
+			converter:      &salaryConverter{testRates, "EUR", 1.1328, nil},
+			salary:         &domain.Salary{Amount: 0, Currency: "GBP"},
+			expectedReason: reasonRateMissing,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			salary := *testCase.salary
+
+			wantResult, err := testCase.converter.convert(testCase.salary)
+
+			if len(testCase.expectedReason) > 0 {
+				var conversionError *domain.ConversionError
+				assert.ErrorAs(t, err, &conversionError)
+				assert.Equal(t, testCase.expectedReason, conversionError.Reason)
+				assert.Equal(t, testCase.converter.currency, conversionError.Currency)
+				assert.Nil(t, wantResult)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+			// the salary read from the database is never changed
+			if !math.IsNaN(salary.Amount) && !math.IsNaN(salary.AmountUSD) {
+				assert.Equal(t, salary, *testCase.salary)
+			}
+		})
+	}
+}
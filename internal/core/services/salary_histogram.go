@@ -0,0 +1,110 @@
+package services
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/domain/response"
+	"math"
+)
+
+// autoBuckets is the number of buckets of the automatic width when the quartiles of the salaries are equal
+const autoBuckets = 10
+
+// niceFractions are the leading digits of the automatic bucket widths, like 200, 250 or 500
+var niceFractions = []float64{1, 2, 2.5, 5, 10}
+
+func (ss SalaryService) validateHistogramRequest(histogramRequest *request.SalaryHistogramRequest) error {
+	if histogramRequest.BucketWidth != nil && histogramRequest.Buckets != 0 {
+		return domain.NewValidationError("Use either bucketWidth or buckets")
+	}
+	if histogramRequest.BucketWidth != nil && *histogramRequest.BucketWidth <= 0 {
+		return domain.NewValidationError("bucketWidth must be greater than 0")
+	}
+	if histogramRequest.Buckets < 0 || histogramRequest.Buckets > ss.statsConfig.MaxBuckets {
+		return domain.NewValidationError("buckets must be between 1 and %d", ss.statsConfig.MaxBuckets)
+	}
+	if isRangeReversed(histogramRequest.OutlierMin, histogramRequest.OutlierMax) {
+		return domain.NewValidationError("outlierMin must not be greater than outlierMax")
+	}
+	return nil
+}
+
+// histogramLayout places the buckets between the outlier bounds in the currency of the statistics.
+// The bounds are OutlierFactor interquartile ranges outside the quartiles unless the request sets them.
+// Fixed and automatic widths start the buckets at a multiple of the width.
+func (ss SalaryService) histogramLayout(histogramRequest *request.SalaryHistogramRequest, stats *response.SalaryStatsResponse) (*domain.HistogramLayout, error) {
+	iqr := stats.P75 - stats.P25
+	lower := math.Max(stats.Min, stats.P25-ss.statsConfig.OutlierFactor*iqr)
+	upper := math.Min(stats.Max, stats.P75+ss.statsConfig.OutlierFactor*iqr)
+	if histogramRequest.OutlierMin != nil {
+		lower = *histogramRequest.OutlierMin
+	}
+	if histogramRequest.OutlierMax != nil {
+		upper = *histogramRequest.OutlierMax
+	}
+	if lower > upper {
+		return nil, domain.NewValidationError("The outlier bounds %v and %v leave no salaries for the buckets", lower, upper)
+	}
+	layout := &domain.HistogramLayout{Lower: lower, Upper: upper}
+	span := upper - lower
+
+	if histogramRequest.Buckets > 0 {
+		layout.Start = lower
+		layout.Buckets = histogramRequest.Buckets
+		layout.Width = span / float64(histogramRequest.Buckets)
+		if span == 0 {
+			layout.Width = 1
+			layout.Buckets = 1
+		}
+		return layout, nil
+	}
+
+	if histogramRequest.BucketWidth != nil {
+		layout.Width = *histogramRequest.BucketWidth
+	} else {
+		// Freedman–Diaconis rule
+		layout.Width = niceWidth(2 * iqr / math.Cbrt(float64(stats.Count)))
+		if layout.Width == 0 {
+			layout.Width = niceWidth(span / autoBuckets)
+		}
+		if layout.Width == 0 {
+			layout.Width = 1
+		}
+		if span/layout.Width > float64(ss.statsConfig.MaxBuckets-1) {
+			layout.Width = niceWidth(span / float64(ss.statsConfig.MaxBuckets-1))
+		}
+	}
+
+	layout.Start = math.Floor(lower/layout.Width) * layout.Width
+	layout.Buckets = int(math.Max(1, math.Ceil((upper-layout.Start)/layout.Width)))
+	if layout.Buckets > ss.statsConfig.MaxBuckets {
+		return nil, domain.NewValidationError("bucketWidth %v gives %d buckets, at most %d are allowed",
+			layout.Width, layout.Buckets, ss.statsConfig.MaxBuckets)
+	}
+	return layout, nil
+}
+
+// niceWidth rounds the width up to a number starting with one of niceFractions
+func niceWidth(width float64) float64 {
+	if width <= 0 {
+		return 0
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(width)))
+	for _, fraction := range niceFractions {
+		if width <= fraction*magnitude*(1+1e-9) {
+			return fraction * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// usdLayout converts the layout in a currency with the USD coefficient rate to USD amounts
+func usdLayout(layout *domain.HistogramLayout, rate float64) *domain.HistogramLayout {
+	return &domain.HistogramLayout{
+		Start:   layout.Start * rate,
+		Width:   layout.Width * rate,
+		Buckets: layout.Buckets,
+		Lower:   layout.Lower * rate,
+		Upper:   layout.Upper * rate,
+	}
+}
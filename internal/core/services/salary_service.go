@@ -1,8 +1,9 @@
 package services
 
 import (
-	"bytes"
+	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"github.com/inkoba/app_for_HR/internal/config"
 	"github.com/inkoba/app_for_HR/internal/core/domain"
@@ -10,173 +11,995 @@ import (
 	"github.com/inkoba/app_for_HR/internal/core/domain/response"
 	"github.com/inkoba/app_for_HR/internal/core/ports"
 	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	currencyUSD = "USD"
-	currencyEUR = "EUR"
-	currencyRUS = "RUS"
 
 	bitSize = 64
 )
-const (
-	twoElementsSalary   = 2
-	firstElementSalary  = 0
-	secondElementSalary = 1
-)
 
 const (
-	indexSalary           = 23
-	indexLevelOfSeniority = 27
-	indexYearsTotal       = 29
-	indexCountry          = 37
-	indexLevelOfEnglish   = 25
+	defaultBatchSize     = 1000
+	dryRunSampleSize     = 10
+	defaultMinGroupSize  = 5
+	maxGroupByDimension  = 2
+	defaultOutlierFactor = 1.5
+	defaultMaxBuckets    = 100
+	defaultPageLimit     = 100
+	maxPageLimit         = 1000
 )
 
 type SalaryService struct {
 	salaryRepository ports.ISalaryRepository
-	currencyConfig   config.CurrencyConfig
+	columnMapping    ports.IColumnMappingService
+	exchangeRates    ports.IExchangeRateService
+	seniority        ports.ISeniorityTaxonomyService
+	batchSize        int
+	statsConfig      config.StatsConfig
 	logger           *logrus.Logger
 }
 
 var _ ports.ISalaryService = (*SalaryService)(nil)
 
-func NewSalaryService(currencyConfig config.CurrencyConfig, repository ports.ISalaryRepository, logger *logrus.Logger) *SalaryService {
+func NewSalaryService(exchangeRates ports.IExchangeRateService, importConfig config.ImportConfig, statsConfig config.StatsConfig, repository ports.ISalaryRepository, columnMapping ports.IColumnMappingService, seniority ports.ISeniorityTaxonomyService, logger *logrus.Logger) *SalaryService {
+	batchSize := importConfig.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	if statsConfig.MinGroupSize <= 0 {
+		statsConfig.MinGroupSize = defaultMinGroupSize
+	}
+	if statsConfig.OutlierFactor <= 0 {
+		statsConfig.OutlierFactor = defaultOutlierFactor
+	}
+	if statsConfig.MaxBuckets < 2 {
+		statsConfig.MaxBuckets = defaultMaxBuckets
+	}
 	return &SalaryService{
 		repository,
-		currencyConfig,
+		columnMapping,
+		exchangeRates,
+		seniority,
+		batchSize,
+		statsConfig,
 		logger,
 	}
 }
 
-func (ss SalaryService) Create(file []byte) (*response.SalaryUploadReport, error) {
+// Create loads the file into a staging dataset and makes it live only after every row has been stored.
+// In append and upsert modes the staging dataset starts as a copy of the live one.
+// A dry run only parses the file and returns the report with the first parsed rows.
+func (ss SalaryService) Create(ctx context.Context, upload *request.SalaryUpload) (*response.SalaryUploadReport, error) {
+	if err := validateUploadMode(upload); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(upload.File)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil && err != io.EOF {
+		ss.logger.Error(err)
+		return nil, err
+	}
 
-	//Clean up salaries from storage
-	err := ss.salaryRepository.DeleteAll()
+	mapping, err := ss.columnMapping.Resolve(header)
 	if err != nil {
 		ss.logger.Error(err)
 		return nil, err
 	}
+	if _, ok := mapping.Indexes[domain.ColumnResponseId]; !ok && upload.Mode == request.UploadModeUpsert {
+		return nil, domain.NewValidationError("Upsert mode requires the %s column in the file", domain.ColumnResponseId)
+	}
+
+	// every row of one import is converted with the rates of the collection date
+	var collectedAt *time.Time
+	if !upload.CollectedAt.IsZero() {
+		collectedAt = &upload.CollectedAt
+	}
+	rates, err := ss.exchangeRates.GetRates(collectedAt)
+	if err != nil {
+		return nil, err
+	}
+	upload.RatesDate, upload.Rates = rates.Date, rates.Rates
 
-	ss.logger.Info("Old documents in collection deleted successfully")
+	if upload.DryRun {
+		return ss.dryRun(ctx, reader, mapping, rates, upload)
+	}
 
-	lines, err := csv.NewReader(bytes.NewReader(file)).ReadAll()
+	dataset, err := ss.salaryRepository.CreateDataset(upload)
 	if err != nil {
 		ss.logger.Error(err)
 		return nil, err
 	}
+
+	if upload.Mode != request.UploadModeReplace {
+		err = ss.salaryRepository.CopyActiveDataset(dataset.Id)
+	}
+	store := ss.salaryRepository.Create
+	if upload.Mode == request.UploadModeUpsert {
+		store = ss.salaryRepository.Upsert
+	}
+	var report *response.SalaryUploadReport
+	if err == nil {
+		report, err = ss.importRows(ctx, reader, mapping, rates, dataset, store, upload.Progress)
+	}
+	if err == nil {
+		err = ss.salaryRepository.ActivateDataset(dataset.Id)
+	}
+	if err != nil {
+		ss.logger.Error(err)
+		ss.discardDataset(dataset)
+		return nil, err
+	}
+
+	ss.logger.Info("Salary dataset activated successfully ", dataset.Id.Hex())
+	report.Version = dataset.Id.Hex()
+	return report, nil
+}
+
+func (ss SalaryService) dryRun(ctx context.Context, reader *csv.Reader, mapping *domain.ColumnMapping, rates *domain.ExchangeRates,
+	upload *request.SalaryUpload) (*response.SalaryUploadReport, error) {
+	dataset := &domain.SalaryDataset{
+		Mode:      upload.Mode,
+		FileName:  upload.FileName,
+		Uploader:  upload.Uploader,
+		CreatedAt: time.Now(),
+	}
+
+	var samples []*response.SalarySample
+	collectSamples := func(salaries []*domain.Salary) error {
+		for _, salary := range salaries {
+			if len(samples) == dryRunSampleSize {
+				break
+			}
+			samples = append(samples, &response.SalarySample{
+				Amount:           salary.Amount,
+				AmountMin:        salary.AmountMin,
+				AmountMax:        salary.AmountMax,
+				Currency:         salary.Currency,
+				AmountUSD:        salary.AmountUSD,
+				LevelOfSeniority: salary.LevelOfSeniority,
+				SeniorityRank:    salary.SeniorityRank,
+				YearsTotal:       salary.YearsTotal,
+				Country:          salary.Country,
+				LevelOfEnglish:   salary.LevelOfEnglish,
+				ResponseId:       salary.ResponseId,
+			})
+		}
+		return nil
+	}
+
+	report, err := ss.importRows(ctx, reader, mapping, rates, dataset, collectSamples, upload.Progress)
+	if err != nil {
+		ss.logger.Error(err)
+		return nil, err
+	}
+	report.Samples = samples
+	return report, nil
+}
+
+func (ss SalaryService) Rollback() (*domain.SalaryDataset, error) {
+	dataset, err := ss.salaryRepository.RollbackDataset()
+	if err != nil {
+		ss.logger.Error("Error rolling back salary dataset: ", err)
+		return nil, err
+	}
+	return dataset, nil
+}
+
+func (ss SalaryService) GetAllVersions() ([]*domain.SalaryDataset, error) {
+	datasets, err := ss.salaryRepository.GetAllDatasets()
+	if err != nil {
+		ss.logger.Error("Error getting salary datasets: ", err)
+		return nil, err
+	}
+	return datasets, nil
+}
+
+func (ss SalaryService) ActivateVersion(id string) (*domain.SalaryDataset, error) {
+	datasetId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrDatasetNotFound
+	}
+
+	dataset, err := ss.salaryRepository.GetDataset(datasetId)
+	if err != nil {
+		ss.logger.Error("Error getting salary dataset: ", err)
+		return nil, err
+	}
+	if dataset.Status != domain.DatasetStatusReady {
+		return nil, domain.ErrDatasetNotFound
+	}
+
+	err = ss.salaryRepository.ActivateDataset(datasetId)
+	if err != nil {
+		ss.logger.Error("Error activating salary dataset: ", err)
+		return nil, err
+	}
+	return ss.salaryRepository.GetDataset(datasetId)
+}
+
+func (ss SalaryService) DeleteVersion(id string) error {
+	datasetId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrDatasetNotFound
+	}
+
+	active, err := ss.salaryRepository.GetActiveDataset()
+	if err != nil && err != domain.ErrDatasetNotFound {
+		ss.logger.Error("Error getting active salary dataset: ", err)
+		return err
+	}
+	if active != nil && active.Id == datasetId {
+		return domain.ErrDatasetActive
+	}
+
+	err = ss.salaryRepository.DeleteDataset(datasetId)
+	if err != nil {
+		ss.logger.Error("Error deleting salary dataset: ", err)
+		return err
+	}
+	return nil
+}
+
+// MigrateLegacySalaries maps the levels of seniority of the salaries stored before the seniority taxonomy existed
+// to the taxonomy and converts the salaries stored before numeric amounts and datasets existed to USD
+// with the current rates of the exchange rate service
+func (ss SalaryService) MigrateLegacySalaries() error {
+	err := ss.salaryRepository.MigrateLegacySeniority(ss.seniority.GetSeniority())
+	if err != nil {
+		ss.logger.Error("Error migrating legacy levels of seniority: ", err)
+		return err
+	}
+
+	rates, err := ss.exchangeRates.GetRates(nil)
+	if err != nil {
+		return err
+	}
+	usdRates := map[string]float64{currencyUSD: 1}
+	for currency, rate := range rates.Rates {
+		usdRates[currency] = rate
+	}
+
+	err = ss.salaryRepository.MigrateLegacySalaries(usdRates)
+	if err != nil {
+		ss.logger.Error("Error migrating legacy salaries: ", err)
+		return err
+	}
+	return nil
+}
+
+// importRows reads the file row by row and passes valid salaries to store in batches of the configured size,
+// levels of seniority that are not in the taxonomy are stored as they are written and listed in the report
+func (ss SalaryService) importRows(ctx context.Context, reader *csv.Reader, mapping *domain.ColumnMapping, rates *domain.ExchangeRates, dataset *domain.SalaryDataset,
+	store func(salaries []*domain.Salary) error, progress func(report *response.SalaryUploadReport)) (*response.SalaryUploadReport, error) {
+	if progress == nil {
+		progress = func(*response.SalaryUploadReport) {}
+	}
+
 	report := response.SalaryUploadReport{}
-	var salaries []*domain.Salary
-	for index, line := range lines {
-		if index == 0 {
-			continue
+	taxonomy := ss.seniority.GetSeniority()
+	unmapped := map[string]bool{}
+	salaries := make([]*domain.Salary, 0, ss.batchSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		line, err := reader.Read()
+		if err == io.EOF {
+			break
 		}
 
 		report.TotalRecords++
 
-		if isLineNotValid(line) {
-			errorHandler(&report, fmt.Errorf("Line %d is not valid", index))
+		if err != nil {
+			var parseError *csv.ParseError
+			if !errors.As(err, &parseError) {
+				return nil, err
+			}
+			errorHandler(&report, &response.RowError{
+				Line:    parseError.StartLine,
+				Code:    response.RowErrorInvalidRow,
+				Message: parseError.Err.Error(),
+			})
 			continue
 		}
 
-		salaryElements := strings.Split(line[indexSalary], " ")
+		lineNumber, _ := reader.FieldPos(0)
+		salary, rowErrors := ss.parseSalary(line, mapping, rates, taxonomy, lineNumber)
+		if len(rowErrors) == 0 && dataset.Mode == request.UploadModeUpsert && len(salary.ResponseId) == 0 {
+			rowErrors = append(rowErrors, &response.RowError{
+				Line:    lineNumber,
+				Column:  domain.ColumnResponseId,
+				Code:    response.RowErrorMissingResponseId,
+				Message: "Upsert mode requires a response id in every row",
+			})
+		}
+		if len(rowErrors) > 0 {
+			errorHandler(&report, rowErrors...)
+			continue
+		}
 
-		if len(salaryElements) == twoElementsSalary {
-			for index, elem := range salaryElements {
-				if index == firstElementSalary {
-					salaryElements[firstElementSalary] = elem
-				} else {
-					salaryElements[secondElementSalary] = elem
-				}
+		if salary.SeniorityRank == 0 {
+			unmapped[salary.LevelOfSeniority] = true
+		}
+		salary.DatasetId = dataset.Id
+		salary.FileName = dataset.FileName
+		salary.Uploader = dataset.Uploader
+		salary.UploadedAt = dataset.CreatedAt
+		salaries = append(salaries, salary)
+		if len(salaries) == ss.batchSize {
+			if err := store(salaries); err != nil {
+				return nil, err
 			}
-		} else {
-			err := fmt.Errorf("The salary field has more elements than being processed")
-			errorHandler(&report, err)
-			continue
+			salaries = salaries[:0]
+			progress(&report)
+		}
+	}
+
+	if len(salaries) > 0 {
+		if err := store(salaries); err != nil {
+			return nil, err
 		}
+	}
+	progress(&report)
 
-		line[indexSalary] = strings.Join(salaryElements, " ")
+	for level := range unmapped {
+		report.UnmappedSeniority = append(report.UnmappedSeniority, level)
+	}
+	if len(report.UnmappedSeniority) > 0 {
+		sort.Strings(report.UnmappedSeniority)
+		ss.logger.Warn("Levels of seniority missing from the taxonomy are stored as they are written: ",
+			strings.Join(report.UnmappedSeniority, ", "))
+	}
+	return &report, nil
+}
 
-		userDataOnSalary := domain.Salary{
-			Salary:           salaryElements[firstElementSalary],
-			Currency:         salaryElements[secondElementSalary],
-			LevelOfSeniority: line[indexLevelOfSeniority],
-			YearsTotal:       line[indexYearsTotal],
-			Country:          line[indexCountry],
-			LevelOfEnglish:   line[indexLevelOfEnglish],
+func (ss SalaryService) discardDataset(dataset *domain.SalaryDataset) {
+	if err := ss.salaryRepository.DeleteDataset(dataset.Id); err != nil {
+		ss.logger.Error("Error deleting staging salary dataset ", dataset.Id.Hex(), ": ", err)
+	}
+}
+
+// parseSalary converts the row to a salary with numeric amount and years and the canonical level of seniority,
+// the raw values are kept as they are
+func (ss SalaryService) parseSalary(line []string, mapping *domain.ColumnMapping, rates *domain.ExchangeRates, taxonomy *domain.SeniorityTaxonomy,
+	lineNumber int) (*domain.Salary, []*response.RowError) {
+	if rowErrors := missingValues(line, mapping, lineNumber); len(rowErrors) > 0 {
+		return nil, rowErrors
+	}
+
+	rawSalary, _ := mapping.Value(line, domain.ColumnSalary)
+	rawYearsTotal, _ := mapping.Value(line, domain.ColumnYearsTotal)
+	salaryError := func(code string, message string) *response.RowError {
+		return &response.RowError{
+			Line:    lineNumber,
+			Column:  domain.ColumnSalary,
+			Value:   rawSalary,
+			Code:    code,
+			Message: message,
 		}
-		salaries = append(salaries, &userDataOnSalary)
 	}
 
-	err = ss.salaryRepository.Create(salaries)
-	return &report, err
+	var rowErrors []*response.RowError
+	var amountUSD float64
+	amount, err := parseSalaryAmount(rawSalary)
+	var unknownCurrency *domain.UnknownCurrencyError
+	if errors.As(err, &unknownCurrency) {
+		rowErrors = append(rowErrors, salaryError(response.RowErrorUnknownCurrency, err.Error()))
+	} else if err != nil {
+		rowErrors = append(rowErrors, salaryError(response.RowErrorInvalidSalary, err.Error()))
+	} else {
+		var ok bool
+		amountUSD, ok = rates.Convert(amount.Mean(), amount.Currency, currencyUSD)
+		if !ok {
+			rowErrors = append(rowErrors, salaryError(response.RowErrorUnknownCurrency,
+				fmt.Sprintf("The currency %s can not be converted to USD", amount.Currency)))
+		}
+	}
+
+	yearsTotal, err := parseNumber(rawYearsTotal)
+	if err != nil {
+		rowErrors = append(rowErrors, &response.RowError{
+			Line:    lineNumber,
+			Column:  domain.ColumnYearsTotal,
+			Value:   rawYearsTotal,
+			Code:    response.RowErrorInvalidYears,
+			Message: "The years of experience is not a number",
+		})
+	}
+	if len(rowErrors) > 0 {
+		return nil, rowErrors
+	}
+
+	rawLevelOfSeniority, _ := mapping.Value(line, domain.ColumnLevelOfSeniority)
+	levelOfSeniority, seniorityRank := strings.TrimSpace(rawLevelOfSeniority), 0
+	if level, ok := taxonomy.Level(rawLevelOfSeniority); ok {
+		levelOfSeniority, seniorityRank = level.Name, level.Rank
+	}
+	country, _ := mapping.Value(line, domain.ColumnCountry)
+	levelOfEnglish, _ := mapping.Value(line, domain.ColumnLevelOfEnglish)
+	responseId, _ := mapping.Value(line, domain.ColumnResponseId)
+
+	return &domain.Salary{
+		Amount:              amount.Mean(),
+		AmountMin:           amount.Min,
+		AmountMax:           amount.Max,
+		Currency:            amount.Currency,
+		AmountUSD:           round2(amountUSD),
+		LevelOfSeniority:    levelOfSeniority,
+		SeniorityRank:       seniorityRank,
+		YearsTotal:          yearsTotal,
+		Country:             country,
+		LevelOfEnglish:      levelOfEnglish,
+		ResponseId:          responseId,
+		RawSalary:           rawSalary,
+		RawYearsTotal:       rawYearsTotal,
+		RawLevelOfSeniority: rawLevelOfSeniority,
+	}, nil
 }
 
-func (ss SalaryService) GetSalariesByFilter(salaryFilteringCondition *request.ConditionForFilteringSalaries) ([]*response.SalariesResponse, error) {
-	filteredSalaries, err := ss.salaryRepository.GetFilteredSalaries(salaryFilteringCondition)
+// usdRate returns the ISO 4217 code of the currency and its coefficient converting it to USD at the rates
+// of the USD amounts selected by the condition
+func (ss SalaryService) usdRate(currency string, condition *request.ConditionForFilteringSalaries) (string, float64, error) {
+	currency, err := domain.NormalizeCurrency(currency)
 	if err != nil {
-		ss.logger.Error("Error get filtered salaries: ")
-		return nil, err
+		return "", 0, err
+	}
+	if currency == currencyUSD {
+		return currency, 1, nil
 	}
-	var filteredResponse []*response.SalariesResponse
-	for _, filteredElem := range filteredSalaries {
+	rates, err := ss.conditionRates(condition)
+	if err != nil {
+		return "", 0, err
+	}
+	return ratesUSDRate(currency, rates)
+}
 
-		if isCurrensyNotValid(filteredElem, ss) {
-			continue
+// ratesUSDRate returns the ISO 4217 code of the currency and its coefficient converting it to USD in the rates
+func ratesUSDRate(currency string, rates *domain.ExchangeRates) (string, float64, error) {
+	currency, err := domain.NormalizeCurrency(currency)
+	if err != nil {
+		return "", 0, err
+	}
+	rate, ok := rates.Rate(currency, currencyUSD)
+	if !ok {
+		return "", 0, domain.NewValidationError("Currency %s can not be converted to USD", currency)
+	}
+	return currency, rate, nil
+}
+
+// conditionRates returns the exchange rates the USD amounts selected by the condition were converted with:
+// today's rates or the rates saved with the requested or live dataset. Datasets created before the rates were saved
+// use the stored rates of their rates date, salaries imported before datasets existed the current rates.
+func (ss SalaryService) conditionRates(condition *request.ConditionForFilteringSalaries) (*domain.ExchangeRates, error) {
+	if condition.RateDate == request.RateDateToday {
+		return ss.exchangeRates.GetRates(nil)
+	}
+
+	dataset, err := ss.selectedDataset(condition.Version)
+	if err != nil {
+		ss.logger.Error("Error getting salary dataset: ", err)
+		return nil, err
+	}
+	if dataset == nil {
+		return ss.exchangeRates.GetRates(nil)
+	}
+	if len(dataset.Rates) > 0 {
+		rates := &domain.ExchangeRates{Rates: dataset.Rates}
+		if dataset.RatesDate != nil {
+			rates.Date = *dataset.RatesDate
 		}
+		return rates, nil
+	}
+	return ss.exchangeRates.GetRates(dataset.RatesDate)
+}
 
-		result := response.SalariesResponse{
-			Salary:           filteredElem.Salary,
-			LevelOfSeniority: filteredElem.LevelOfSeniority,
-			YearsTotal:       filteredElem.YearsTotal,
-			Country:          filteredElem.Country,
+// selectedDataset returns the dataset of the version or the live dataset when no version is given,
+// it is nil when no dataset has been activated yet
+func (ss SalaryService) selectedDataset(version string) (*domain.SalaryDataset, error) {
+	if len(strings.TrimSpace(version)) == 0 {
+		dataset, err := ss.salaryRepository.GetActiveDataset()
+		if err == domain.ErrDatasetNotFound {
+			return nil, nil
 		}
+		return dataset, err
+	}
 
-		filteredResponse = append(filteredResponse, &result)
+	datasetId, err := primitive.ObjectIDFromHex(strings.TrimSpace(version))
+	if err != nil {
+		return nil, domain.ErrDatasetNotFound
 	}
-	return filteredResponse, nil
+	return ss.salaryRepository.GetDataset(datasetId)
 }
 
-func errorHandler(report *response.SalaryUploadReport, err error) {
-	report.Errors = append(report.Errors, err.Error())
-	report.SkippedRecords++
+func parseNumber(value string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), bitSize)
 }
 
-func isLineNotValid(line []string) bool {
-	return isIndexOfSalaryEmpty(line) ||
-		len(strings.TrimSpace(line[indexLevelOfSeniority])) == 0 ||
-		len(strings.TrimSpace(line[indexYearsTotal])) == 0 ||
-		len(strings.TrimSpace(line[indexCountry])) == 0 ||
-		len(strings.TrimSpace(line[indexLevelOfEnglish])) == 0
+// validateUploadMode sets the replace mode when the upload has no mode and checks that the mode is supported
+func validateUploadMode(upload *request.SalaryUpload) error {
+	if len(upload.Mode) == 0 {
+		upload.Mode = request.UploadModeReplace
+	}
+	switch upload.Mode {
+	case request.UploadModeReplace, request.UploadModeAppend, request.UploadModeUpsert:
+		return nil
+	}
+	return domain.NewValidationError("Upload mode %q is not supported", upload.Mode)
 }
 
-func isIndexOfSalaryEmpty(line []string) bool {
-	return len(strings.TrimSpace(line[indexSalary])) == 0
+// CountSalariesByFilter checks the page request, fills in its default limit and sort order
+// and counts the salaries matching its filter
+func (ss SalaryService) CountSalariesByFilter(pageRequest *request.SalaryPageRequest) (*response.SalariesPage, error) {
+	if err := validatePageRequest(pageRequest); err != nil {
+		return nil, err
+	}
+	condition, err := ss.usdCondition(&pageRequest.ConditionForFilteringSalaries)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := ss.salaryRepository.CountFilteredSalaries(condition)
+	if err != nil {
+		ss.logger.Error("Error counting filtered salaries: ", err)
+		return nil, err
+	}
+	return &response.SalariesPage{
+		Total:     total,
+		Limit:     pageRequest.Limit,
+		Offset:    pageRequest.Offset,
+		SortBy:    pageRequest.SortBy,
+		SortOrder: pageRequest.SortOrder,
+	}, nil
 }
-func isCurrensyNotValid(filteredElem *domain.Salary, ss SalaryService) bool {
-	return isCurrencyNotConvertible(filteredElem, ss)
+
+// GetSalariesByFilter passes the salaries of the page to each as they are read from the database,
+// converted from their USD amounts to the target currency of the page request
+func (ss SalaryService) GetSalariesByFilter(pageRequest *request.SalaryPageRequest, each func(salary *response.SalariesResponse) error) error {
+	if err := validatePageRequest(pageRequest); err != nil {
+		return err
+	}
+	condition, err := ss.usdCondition(&pageRequest.ConditionForFilteringSalaries)
+	if err != nil {
+		return err
+	}
+	converter, err := ss.newSalaryConverter(pageRequest.TargetCurrency, condition)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	page := *pageRequest
+	page.ConditionForFilteringSalaries = *condition
+	err = ss.salaryRepository.GetFilteredSalaries(&page, func(salary *domain.Salary) error {
+		result := &response.SalariesResponse{
+			LevelOfSeniority: salary.LevelOfSeniority,
+			YearsTotal:       strconv.FormatFloat(salary.YearsTotal, 'f', -1, bitSize),
+			Country:          salary.Country,
+			OriginalAmount:   salary.Amount,
+			OriginalCurrency: salary.Currency,
+			Currency:         converter.currency,
+		}
+		conversion, err := converter.convert(salary)
+		if err != nil {
+			failed++
+			result.ConversionError = err.Error()
+			return each(result)
+		}
+		result.Salary = fmt.Sprint(math.Round(conversion.Amount))
+		result.OriginalCurrency = conversion.OriginalCurrency
+		result.Amount = &conversion.Amount
+		result.Rate = &conversion.Rate
+		return each(result)
+	})
+	if err != nil {
+		ss.logger.Error("Error get filtered salaries: ", err)
+		return err
+	}
+	ss.logConversionFailures(failed, converter.currency)
+	return nil
+}
+
+// ExportSalaries passes every salary matching the filter to each with its amount converted to the requested currency
+func (ss SalaryService) ExportSalaries(exportRequest *request.SalaryExportRequest, each func(row *response.SalaryExportRow) error) error {
+	condition, err := ss.usdCondition(&exportRequest.ConditionForFilteringSalaries)
+	if err != nil {
+		return err
+	}
+	converter, err := ss.newSalaryConverter(exportRequest.TargetCurrency, condition)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	page := &request.SalaryPageRequest{ConditionForFilteringSalaries: *condition}
+	err = ss.salaryRepository.GetFilteredSalaries(page, func(salary *domain.Salary) error {
+		row := &response.SalaryExportRow{
+			ResponseId:       salary.ResponseId,
+			LevelOfSeniority: salary.LevelOfSeniority,
+			YearsTotal:       salary.YearsTotal,
+			Country:          salary.Country,
+			LevelOfEnglish:   salary.LevelOfEnglish,
+			OriginalAmount:   salary.Amount,
+			OriginalCurrency: salary.Currency,
+			Currency:         converter.currency,
+		}
+		conversion, err := converter.convert(salary)
+		if err != nil {
+			failed++
+			row.ConversionError = err.Error()
+			return each(row)
+		}
+		row.OriginalCurrency = conversion.OriginalCurrency
+		row.Amount = &conversion.Amount
+		return each(row)
+	})
+	if err != nil {
+		ss.logger.Error("Error exporting salaries: ", err)
+		return err
+	}
+	ss.logConversionFailures(failed, converter.currency)
+	return nil
+}
+
+// logConversionFailures warns about the salaries that were returned without a converted amount
+func (ss SalaryService) logConversionFailures(failed int, currency string) {
+	if failed > 0 {
+		ss.logger.Warn(failed, " salaries could not be converted to ", currency)
+	}
 }
-func isCurrencyNotConvertible(filteredElem *domain.Salary, ss SalaryService) bool {
-	switch filteredElem.Currency {
-	case currencyUSD:
-	case currencyEUR:
-		converter(filteredElem, ss.logger, ss.currencyConfig.CoefficientEURtoUSD)
-	case currencyRUS:
-		converter(filteredElem, ss.logger, ss.currencyConfig.CoefficientRUStoUSD)
+
+// validatePageRequest sets the default limit and the ascending order when they are empty
+func validatePageRequest(pageRequest *request.SalaryPageRequest) error {
+	if pageRequest.Limit == 0 {
+		pageRequest.Limit = defaultPageLimit
+	}
+	if pageRequest.Limit < 0 || pageRequest.Limit > maxPageLimit {
+		return domain.NewValidationError("limit must be between 1 and %d", maxPageLimit)
+	}
+	if pageRequest.Offset < 0 {
+		return domain.NewValidationError("offset must not be negative")
+	}
+	if len(pageRequest.SortBy) == 0 {
+		if len(pageRequest.SortOrder) > 0 {
+			return domain.NewValidationError("sortOrder needs sortBy")
+		}
+		return nil
+	}
+	if !containsString(request.SortByFields, pageRequest.SortBy) {
+		return domain.NewValidationError("Salaries can not be sorted by %s, use one of: %s",
+			pageRequest.SortBy, strings.Join(request.SortByFields, ", "))
+	}
+	switch pageRequest.SortOrder {
+	case "":
+		pageRequest.SortOrder = request.SortOrderAsc
+	case request.SortOrderAsc, request.SortOrderDesc:
 	default:
-		ss.logger.Error("CurrencyConfig does not exist for line: ", filteredElem)
-		return true
+		return domain.NewValidationError("sortOrder must be %s or %s", request.SortOrderAsc, request.SortOrderDesc)
+	}
+	return nil
+}
+
+// GetSalaryStats returns the statistics of the selected salaries in the requested currency
+func (ss SalaryService) GetSalaryStats(statsRequest *request.SalaryStatsRequest) (*response.SalaryStatsResponse, error) {
+	condition, err := ss.usdCondition(&statsRequest.ConditionForFilteringSalaries)
+	if err != nil {
+		return nil, err
+	}
+	currency, rate, err := ss.targetCurrency(statsRequest.TargetCurrency, condition)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := ss.salaryRepository.GetSalaryStats(condition)
+	if err != nil {
+		ss.logger.Error("Error getting salary statistics: ", err)
+		return nil, err
+	}
+	return statsResponse(stats, currency, rate), nil
+}
+
+// GetSalaryGroups returns the statistics for every group of the selected salaries,
+// groups with fewer salaries than the configured minimum are only counted as suppressed
+func (ss SalaryService) GetSalaryGroups(groupsRequest *request.SalaryGroupsRequest) (*response.SalaryGroupsResponse, error) {
+	if err := validateGroupBy(groupsRequest.GroupBy); err != nil {
+		return nil, err
+	}
+	condition, err := ss.usdCondition(&groupsRequest.ConditionForFilteringSalaries)
+	if err != nil {
+		return nil, err
+	}
+	currency, rate, err := ss.targetCurrency(groupsRequest.TargetCurrency, condition)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := ss.salaryRepository.GetSalaryGroupStats(condition, groupsRequest.GroupBy)
+	if err != nil {
+		ss.logger.Error("Error getting salary group statistics: ", err)
+		return nil, err
+	}
+
+	result := &response.SalaryGroupsResponse{
+		GroupBy:      groupsRequest.GroupBy,
+		MinGroupSize: ss.statsConfig.MinGroupSize,
+		Groups:       []*response.SalaryGroupResponse{},
+	}
+	for _, group := range groups {
+		if group.Count < ss.statsConfig.MinGroupSize {
+			result.SuppressedGroups++
+			continue
+		}
+		result.Groups = append(result.Groups, &response.SalaryGroupResponse{
+			Group:               group.Group,
+			SalaryStatsResponse: *statsResponse(&group.SalaryStats, currency, rate),
+		})
+	}
+	return result, nil
+}
+
+// GetSalaryHistogram counts the selected salaries in buckets of the requested currency,
+// salaries outside the outlier bounds are counted apart from the buckets
+func (ss SalaryService) GetSalaryHistogram(histogramRequest *request.SalaryHistogramRequest) (*response.SalaryHistogramResponse, error) {
+	if err := ss.validateHistogramRequest(histogramRequest); err != nil {
+		return nil, err
+	}
+	condition, err := ss.usdCondition(&histogramRequest.ConditionForFilteringSalaries)
+	if err != nil {
+		return nil, err
+	}
+	currency, rate, err := ss.targetCurrency(histogramRequest.TargetCurrency, condition)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := ss.salaryRepository.GetSalaryStats(condition)
+	if err != nil {
+		ss.logger.Error("Error getting salary statistics: ", err)
+		return nil, err
+	}
+	result := &response.SalaryHistogramResponse{
+		BaseCurrency: currencyUSD,
+		Currency:     currency,
+		Rate:         roundRate(1 / rate),
+		Count:        stats.Count,
+		Buckets:      []*response.HistogramBucketResponse{},
+	}
+	if stats.Count == 0 {
+		return result, nil
+	}
+
+	layout, err := ss.histogramLayout(histogramRequest, statsResponse(stats, currency, rate))
+	if err != nil {
+		return nil, err
+	}
+	histogram, err := ss.salaryRepository.GetSalaryHistogram(condition, usdLayout(layout, rate))
+	if err != nil {
+		ss.logger.Error("Error getting salary histogram: ", err)
+		return nil, err
+	}
+
+	result.BucketWidth = round2(layout.Width)
+	for i, count := range histogram.Counts {
+		result.Buckets = append(result.Buckets, &response.HistogramBucketResponse{
+			From:  round2(layout.Start + float64(i)*layout.Width),
+			To:    round2(layout.Start + float64(i+1)*layout.Width),
+			Count: count,
+		})
+	}
+	result.Outliers = response.HistogramOutliersResponse{
+		LowerBound: round2(layout.Lower),
+		UpperBound: round2(layout.Upper),
+		Below:      histogram.Below,
+		Above:      histogram.Above,
+	}
+	return result, nil
+}
+
+func validateGroupBy(groupBy []string) error {
+	if len(groupBy) == 0 || len(groupBy) > maxGroupByDimension {
+		return domain.NewValidationError("Salaries can be grouped by one or two of: %s", strings.Join(domain.GroupByDimensions, ", "))
+	}
+	for i, dimension := range groupBy {
+		if !containsString(domain.GroupByDimensions, dimension) {
+			return domain.NewValidationError("Salaries can not be grouped by %s, use one of: %s",
+				dimension, strings.Join(domain.GroupByDimensions, ", "))
+		}
+		if containsString(groupBy[:i], dimension) {
+			return domain.NewValidationError("Salaries can not be grouped by %s twice", dimension)
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
 	}
 	return false
 }
 
-func converter(salary *domain.Salary, logger *logrus.Logger, coefficient float64) {
-	currency, err := strconv.ParseFloat(salary.Salary, bitSize)
+// targetCurrency returns the ISO code of the requested currency, USD when it is empty, and its USD coefficient
+// at the rates of the USD amounts selected by the condition
+func (ss SalaryService) targetCurrency(currency string, condition *request.ConditionForFilteringSalaries) (string, float64, error) {
+	if len(strings.TrimSpace(currency)) == 0 {
+		return currencyUSD, 1, nil
+	}
+	return ss.usdRate(currency, condition)
+}
+
+// statsResponse converts the USD statistics to the currency with the USD coefficient rate
+func statsResponse(stats *domain.SalaryStats, currency string, rate float64) *response.SalaryStatsResponse {
+	fromUSD := func(amount float64) float64 {
+		return round2(amount / rate)
+	}
+	return &response.SalaryStatsResponse{
+		BaseCurrency: currencyUSD,
+		Currency:     currency,
+		Rate:         roundRate(1 / rate),
+		Count:        stats.Count,
+		Min:          fromUSD(stats.Min),
+		Max:          fromUSD(stats.Max),
+		Mean:         fromUSD(stats.Mean),
+		Median:       fromUSD(stats.Median),
+		P10:          fromUSD(stats.P10),
+		P25:          fromUSD(stats.P25),
+		P75:          fromUSD(stats.P75),
+		P90:          fromUSD(stats.P90),
+	}
+}
+
+// round2 rounds the amount to cents
+func round2(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// roundRate rounds an exchange rate to six decimals
+func roundRate(rate float64) float64 {
+	return math.Round(rate*1e6) / 1e6
+}
+
+// usdCondition checks the bounds of the condition and converts the salary bounds to USD at the rates of the USD amounts
+// so the repository can compare them, today's rates are added when the condition asks for them.
+// Levels of seniority are replaced with their canonical names and the seniority bounds with their ranks
+func (ss SalaryService) usdCondition(condition *request.ConditionForFilteringSalaries) (*request.ConditionForFilteringSalaries, error) {
+	if isRangeReversed(condition.SalaryMin, condition.SalaryMax) {
+		return nil, domain.NewValidationError("salaryMin must not be greater than salaryMax")
+	}
+	if isRangeReversed(condition.YearsTotalMin, condition.YearsTotalMax) {
+		return nil, domain.NewValidationError("yearsTotalMin must not be greater than yearsTotalMax")
+	}
+
+	converted := *condition
+	if err := ss.seniorityCondition(&converted); err != nil {
+		return nil, err
+	}
+	switch condition.RateDate {
+	case "", request.RateDateCollection:
+	case request.RateDateToday:
+		rates, err := ss.exchangeRates.GetRates(nil)
+		if err != nil {
+			return nil, err
+		}
+		converted.USDRates = map[string]float64{currencyUSD: 1}
+		for currency, rate := range rates.Rates {
+			converted.USDRates[currency] = rate
+		}
+	default:
+		return nil, domain.NewValidationError("rateDate must be %s or %s", request.RateDateCollection, request.RateDateToday)
+	}
+	if condition.SalaryMin == nil && condition.SalaryMax == nil {
+		return &converted, nil
+	}
+
+	currency := currencyUSD
+	if len(strings.TrimSpace(condition.SalaryCurrency)) > 0 {
+		currency = condition.SalaryCurrency
+	}
+	_, rate, err := ss.usdRate(currency, condition)
 	if err != nil {
-		logger.Error(err)
+		return nil, err
+	}
+
+	converted.SalaryMin = multiply(condition.SalaryMin, rate)
+	converted.SalaryMax = multiply(condition.SalaryMax, rate)
+	converted.SalaryCurrency = currencyUSD
+	return &converted, nil
+}
+
+// seniorityCondition replaces the levels of seniority of the condition copy, the lists are replaced rather than changed
+// so the request stays as it was sent. A level that is not in the taxonomy is kept as it is written
+func (ss SalaryService) seniorityCondition(condition *request.ConditionForFilteringSalaries) error {
+	taxonomy := ss.seniority.GetSeniority()
+	if len(strings.TrimSpace(condition.LevelOfSeniority)) > 0 {
+		condition.LevelOfSeniority = taxonomy.Canonical(condition.LevelOfSeniority)
+	}
+	if condition.LevelsOfSeniority != nil {
+		condition.LevelsOfSeniority = &request.ValuesFilter{
+			In:    canonicalLevels(taxonomy, condition.LevelsOfSeniority.In),
+			NotIn: canonicalLevels(taxonomy, condition.LevelsOfSeniority.NotIn),
+		}
+	}
+
+	rank := func(field string, value string) (*float64, error) {
+		if len(strings.TrimSpace(value)) == 0 {
+			return nil, nil
+		}
+		level, ok := taxonomy.Level(value)
+		if !ok {
+			return nil, domain.NewValidationError("%s %q is not a level of seniority, the levels are %s",
+				field, value, strings.Join(taxonomy.Names(), ", "))
+		}
+		rank := float64(level.Rank)
+		return &rank, nil
+	}
+	var err error
+	if condition.SeniorityRankMin, err = rank("seniorityMin", condition.SeniorityMin); err != nil {
+		return err
+	}
+	if condition.SeniorityRankMax, err = rank("seniorityMax", condition.SeniorityMax); err != nil {
+		return err
+	}
+	if isRangeReversed(condition.SeniorityRankMin, condition.SeniorityRankMax) {
+		return domain.NewValidationError("seniorityMin must not be more senior than seniorityMax")
+	}
+	return nil
+}
+
+func canonicalLevels(taxonomy *domain.SeniorityTaxonomy, levels []string) []string {
+	if levels == nil {
+		return nil
+	}
+	canonical := make([]string, len(levels))
+	for i, level := range levels {
+		canonical[i] = taxonomy.Canonical(level)
+	}
+	return canonical
+}
+
+func isRangeReversed(min *float64, max *float64) bool {
+	return min != nil && max != nil && *min > *max
+}
+
+func multiply(value *float64, coefficient float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	result := *value * coefficient
+	return &result
+}
+
+func errorHandler(report *response.SalaryUploadReport, rowErrors ...*response.RowError) {
+	report.Errors = append(report.Errors, rowErrors...)
+	report.SkippedRecords++
+}
+
+func missingValues(line []string, mapping *domain.ColumnMapping, lineNumber int) []*response.RowError {
+	var rowErrors []*response.RowError
+	for _, column := range requiredColumns {
+		value, ok := mapping.Value(line, column)
+		if !ok || len(value) == 0 {
+			rowErrors = append(rowErrors, &response.RowError{
+				Line:    lineNumber,
+				Column:  column,
+				Code:    response.RowErrorMissingValue,
+				Message: fmt.Sprintf("The %s field is empty", column),
+			})
+		}
 	}
-	salary.Salary = fmt.Sprint(math.Round(currency * coefficient))
+	return rowErrors
 }
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/inkoba/app_for_HR/internal/config"
@@ -10,48 +12,361 @@ import (
 	mock_ports "github.com/inkoba/app_for_HR/internal/core/ports/mocks"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
+var testColumnMapping = &domain.ColumnMapping{
+	Edition: "2022",
+	Indexes: map[string]int{
+		domain.ColumnSalary:           0,
+		domain.ColumnLevelOfSeniority: 1,
+		domain.ColumnYearsTotal:       2,
+		domain.ColumnCountry:          3,
+		domain.ColumnLevelOfEnglish:   4,
+	},
+}
+
+const testSalaryHeader = "Salary,Level of seniority,Years total,Country,Level of English\n"
+
+var testRates = &domain.ExchangeRates{Rates: map[string]float64{"EUR": 1.1328, "RUB": 0.014, "PLN": 0.25}}
+
+var testStatsConfig = config.StatsConfig{MinGroupSize: 5, OutlierFactor: 1.5, MaxBuckets: 20}
+
+var testTaxonomyConfig = config.TaxonomyConfig{Seniority: []config.SeniorityLevelConfig{
+	{Name: "Junior", Aliases: []string{"Jr"}},
+	{Name: "Middle", Aliases: []string{"Mid", "Regular"}},
+	{Name: "Senior", Aliases: []string{"Sr"}},
+	{Name: "Lead", Aliases: []string{"Team lead"}},
+}}
+
+// newTestSeniority returns the seniority taxonomy of testTaxonomyConfig
+func newTestSeniority(t *testing.T) *SeniorityTaxonomyService {
+	t.Helper()
+	service, err := NewSeniorityTaxonomyService(testTaxonomyConfig, logrus.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+var testDataset = &domain.SalaryDataset{
+	Id:        id,
+	Status:    domain.DatasetStatusStaging,
+	FileName:  "salaries.csv",
+	Uploader:  "admin",
+	CreatedAt: time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC),
+}
+
 func TestSalaryService_Create(t *testing.T) {
-	type mockBehavior func(s *mock_ports.MockISalaryRepository, file []byte, salaries []*domain.Salary)
+	type mockBehavior func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary)
 
 	testTable := []struct {
 		name          string
 		file          []byte
+		mode          string
+		readError     error
+		dryRun        bool
+		cancelled     bool
 		mockBehavior  mockBehavior
 		inputData     []*domain.Salary
 		expected      *response.SalaryUploadReport
 		expectedError bool
 	}{
 		{
-			name: "get error when delete all data in database is impossible",
-			file: []byte{},
-			mockBehavior: func(s *mock_ports.MockISalaryRepository, file []byte, salaries []*domain.Salary) {
-				s.EXPECT().DeleteAll().Return(errors.New("Error delete all data in database"))
+			name: "get error when required columns are missing in the header",
+			file: []byte("Salary,Country\n1000 USD,Belarus\n"),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve([]string{"Salary", "Country"}).
+					Return(nil, &domain.MissingColumnsError{Edition: "2022", Columns: []string{domain.ColumnYearsTotal}})
+			},
+			expectedError: true,
+		},
+		{
+			name: "get error when the upload mode is not supported",
+			file: []byte(testSalaryHeader),
+			mode: "merge",
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+			},
+			expectedError: true,
+		},
+		{
+			name: "get error when upsert is requested without the response id column",
+			file: []byte(testSalaryHeader),
+			mode: request.UploadModeUpsert,
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+			},
+			expectedError: true,
+		},
+		{
+			name:   "dry run parses the file without writing to the repository",
+			file:   []byte(testSalaryHeader + "1000 USD,Junior,1,Belarus,B1\n,Middle,3,Poland,B2\n2000 EUR,Senior,5,Latvia,C1\n"),
+			dryRun: true,
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+			},
+			expected: &response.SalaryUploadReport{
+				TotalRecords:   3,
+				SkippedRecords: 1,
+				Errors: []*response.RowError{
+					{Line: 3, Column: domain.ColumnSalary, Code: response.RowErrorMissingValue, Message: "The salary field is empty"},
+				},
+				Samples: []*response.SalarySample{
+					{Amount: 1000, AmountMin: 1000, AmountMax: 1000, Currency: "USD", AmountUSD: 1000, LevelOfSeniority: "Junior", SeniorityRank: 1, YearsTotal: 1, Country: "Belarus", LevelOfEnglish: "B1"},
+					{Amount: 2000, AmountMin: 2000, AmountMax: 2000, Currency: "EUR", AmountUSD: 2265.6, LevelOfSeniority: "Senior", SeniorityRank: 3, YearsTotal: 5, Country: "Latvia", LevelOfEnglish: "C1"},
+				},
+			},
+		},
+		{
+			name:      "staging dataset is discarded when the import is cancelled",
+			file:      []byte(testSalaryHeader + "1000 USD,Junior,1,Belarus,B1\n"),
+			cancelled: true,
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				s.EXPECT().CreateDataset(gomock.Any()).Return(testDataset, nil)
+				s.EXPECT().DeleteDataset(testDataset.Id)
+			},
+			expectedError: true,
+		},
+		{
+			name: "staging dataset is discarded when the live dataset can not be copied",
+			file: []byte(testSalaryHeader + "1000 USD,Junior,1,Belarus,B1\n"),
+			mode: request.UploadModeAppend,
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				s.EXPECT().CreateDataset(gomock.Any()).Return(testDataset, nil)
+				s.EXPECT().CopyActiveDataset(testDataset.Id).Return(errors.New("database is unavailable"))
+				s.EXPECT().DeleteDataset(testDataset.Id)
 			},
 			expectedError: true,
 		},
 		{
-			name: "get error when creating new salaries in database and database is unavailable",
-			file: []byte{},
-			mockBehavior: func(s *mock_ports.MockISalaryRepository, file []byte, salaries []*domain.Salary) {
-				s.EXPECT().DeleteAll()
-				s.EXPECT().Create(salaries).Return(errors.New("Error delete all data in database"))
+			name: "appended salaries are added to a copy of the live dataset",
+			file: []byte(testSalaryHeader + "1000 USD,Junior,1,Belarus,B1\n"),
+			mode: request.UploadModeAppend,
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				gomock.InOrder(
+					s.EXPECT().CreateDataset(gomock.Any()).Return(testDataset, nil),
+					s.EXPECT().CopyActiveDataset(testDataset.Id),
+					s.EXPECT().Create(salaries),
+					s.EXPECT().ActivateDataset(testDataset.Id),
+				)
+			},
+			inputData: []*domain.Salary{
+				{DatasetId: id, FileName: "salaries.csv", Uploader: "admin", UploadedAt: testDataset.CreatedAt, Amount: 1000, AmountMin: 1000, AmountMax: 1000, Currency: "USD", AmountUSD: 1000, LevelOfSeniority: "Junior", SeniorityRank: 1, YearsTotal: 1, Country: "Belarus", LevelOfEnglish: "B1", RawSalary: "1000 USD", RawYearsTotal: "1", RawLevelOfSeniority: "Junior"},
+			},
+			expected: &response.SalaryUploadReport{Version: testDataset.Id.Hex(), TotalRecords: 1},
+		},
+		{
+			name: "upserted salaries replace the rows with the same response id",
+			file: []byte("Response ID," + testSalaryHeader +
+				"r1,1000 USD,Junior,1,Belarus,B1\n" +
+				",2000 USD,Middle,3,Poland,B2\n"),
+			mode: request.UploadModeUpsert,
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(&domain.ColumnMapping{Edition: "2022", Indexes: map[string]int{
+					domain.ColumnResponseId:       0,
+					domain.ColumnSalary:           1,
+					domain.ColumnLevelOfSeniority: 2,
+					domain.ColumnYearsTotal:       3,
+					domain.ColumnCountry:          4,
+					domain.ColumnLevelOfEnglish:   5,
+				}}, nil)
+				upsertDataset := *testDataset
+				upsertDataset.Mode = request.UploadModeUpsert
+				gomock.InOrder(
+					s.EXPECT().CreateDataset(gomock.Any()).Return(&upsertDataset, nil),
+					s.EXPECT().CopyActiveDataset(testDataset.Id),
+					s.EXPECT().Upsert(salaries),
+					s.EXPECT().ActivateDataset(testDataset.Id),
+				)
+			},
+			inputData: []*domain.Salary{
+				{DatasetId: id, FileName: "salaries.csv", Uploader: "admin", UploadedAt: testDataset.CreatedAt, ResponseId: "r1", Amount: 1000, AmountMin: 1000, AmountMax: 1000, Currency: "USD", AmountUSD: 1000, LevelOfSeniority: "Junior", SeniorityRank: 1, YearsTotal: 1, Country: "Belarus", LevelOfEnglish: "B1", RawSalary: "1000 USD", RawYearsTotal: "1", RawLevelOfSeniority: "Junior"},
+			},
+			expected: &response.SalaryUploadReport{
+				Version:        testDataset.Id.Hex(),
+				TotalRecords:   2,
+				SkippedRecords: 1,
+				Errors: []*response.RowError{
+					{Line: 3, Column: domain.ColumnResponseId, Code: response.RowErrorMissingResponseId, Message: "Upsert mode requires a response id in every row"},
+				},
+			},
+		},
+		{
+			name:   "levels of seniority are mapped to the taxonomy and unknown levels are reported",
+			file:   []byte(testSalaryHeader + "1000 USD, sr. ,1,Belarus,B1\n2000 USD,Team-Lead,3,Poland,B2\n3000 USD,Architect,5,Latvia,C1\n"),
+			dryRun: true,
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+			},
+			expected: &response.SalaryUploadReport{
+				TotalRecords: 3,
+				Samples: []*response.SalarySample{
+					{Amount: 1000, AmountMin: 1000, AmountMax: 1000, Currency: "USD", AmountUSD: 1000, LevelOfSeniority: "Senior", SeniorityRank: 3, YearsTotal: 1, Country: "Belarus", LevelOfEnglish: "B1"},
+					{Amount: 2000, AmountMin: 2000, AmountMax: 2000, Currency: "USD", AmountUSD: 2000, LevelOfSeniority: "Lead", SeniorityRank: 4, YearsTotal: 3, Country: "Poland", LevelOfEnglish: "B2"},
+					{Amount: 3000, AmountMin: 3000, AmountMax: 3000, Currency: "USD", AmountUSD: 3000, LevelOfSeniority: "Architect", YearsTotal: 5, Country: "Latvia", LevelOfEnglish: "C1"},
+				},
+				UnmappedSeniority: []string{"Architect"},
+			},
+		},
+		{
+			name: "get error when the staging dataset can not be created",
+			file: []byte(testSalaryHeader),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				s.EXPECT().CreateDataset(gomock.Any()).Return(nil, errors.New("database is unavailable"))
+			},
+			expectedError: true,
+		},
+		{
+			name: "staging dataset is discarded when creating new salaries in database fails",
+			file: []byte(testSalaryHeader + "1000 USD,Junior,1,Belarus,B1\n"),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				s.EXPECT().CreateDataset(gomock.Any()).Return(testDataset, nil)
+				s.EXPECT().Create(gomock.Any()).Return(errors.New("database is unavailable"))
+				s.EXPECT().DeleteDataset(testDataset.Id)
+			},
+			expectedError: true,
+		},
+		{
+			name:      "staging dataset is discarded when the file can not be read",
+			file:      []byte(testSalaryHeader + "1000 USD,Junior,1,Belarus,B1\n"),
+			readError: errors.New("connection reset by peer"),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				s.EXPECT().CreateDataset(gomock.Any()).Return(testDataset, nil)
+				s.EXPECT().DeleteDataset(testDataset.Id)
+			},
+			expectedError: true,
+		},
+		{
+			name: "staging dataset is discarded when it can not be activated",
+			file: []byte(testSalaryHeader + "1000 USD,Junior,1,Belarus,B1\n"),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				s.EXPECT().CreateDataset(gomock.Any()).Return(testDataset, nil)
+				s.EXPECT().Create(gomock.Any())
+				s.EXPECT().ActivateDataset(testDataset.Id).Return(errors.New("database is unavailable"))
+				s.EXPECT().DeleteDataset(testDataset.Id)
 			},
 			expectedError: true,
 		},
+		{
+			name: "empty dataset is activated when the file contains only the header",
+			file: []byte(testSalaryHeader),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				s.EXPECT().CreateDataset(gomock.Any()).Return(testDataset, nil)
+				s.EXPECT().ActivateDataset(testDataset.Id)
+			},
+			expected: &response.SalaryUploadReport{Version: testDataset.Id.Hex()},
+		},
+		{
+			name: "salaries are stored in batches",
+			file: []byte(testSalaryHeader +
+				"1000 USD,Junior,1,Belarus,B1\n" +
+				"2000 USD,Middle,3,Poland,B2\n" +
+				"3000 EUR,Senior,5,Latvia,C1\n"),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				gomock.InOrder(
+					s.EXPECT().CreateDataset(gomock.Any()).Return(testDataset, nil),
+					s.EXPECT().Create(salaries[:2]),
+					s.EXPECT().Create(salaries[2:]),
+					s.EXPECT().ActivateDataset(testDataset.Id),
+				)
+			},
+			inputData: []*domain.Salary{
+				{DatasetId: id, FileName: "salaries.csv", Uploader: "admin", UploadedAt: testDataset.CreatedAt, Amount: 1000, AmountMin: 1000, AmountMax: 1000, Currency: "USD", AmountUSD: 1000, LevelOfSeniority: "Junior", SeniorityRank: 1, YearsTotal: 1, Country: "Belarus", LevelOfEnglish: "B1", RawSalary: "1000 USD", RawYearsTotal: "1", RawLevelOfSeniority: "Junior"},
+				{DatasetId: id, FileName: "salaries.csv", Uploader: "admin", UploadedAt: testDataset.CreatedAt, Amount: 2000, AmountMin: 2000, AmountMax: 2000, Currency: "USD", AmountUSD: 2000, LevelOfSeniority: "Middle", SeniorityRank: 2, YearsTotal: 3, Country: "Poland", LevelOfEnglish: "B2", RawSalary: "2000 USD", RawYearsTotal: "3", RawLevelOfSeniority: "Middle"},
+				{DatasetId: id, FileName: "salaries.csv", Uploader: "admin", UploadedAt: testDataset.CreatedAt, Amount: 3000, AmountMin: 3000, AmountMax: 3000, Currency: "EUR", AmountUSD: 3398.4, LevelOfSeniority: "Senior", SeniorityRank: 3, YearsTotal: 5, Country: "Latvia", LevelOfEnglish: "C1", RawSalary: "3000 EUR", RawYearsTotal: "5", RawLevelOfSeniority: "Senior"},
+			},
+			expected: &response.SalaryUploadReport{Version: testDataset.Id.Hex(), TotalRecords: 3},
+		},
+		{
+			name: "amounts and years are stored as numbers with the currency converted to USD",
+			file: []byte(testSalaryHeader +
+				"120000 RUS,Middle,2.5,Russia,B1\n" +
+				"1/2 USD,Junior,1,Belarus,B1\n" +
+				"1000 GBP,Junior,1,Belarus,B1\n" +
+				"1000 ABC,Junior,1,Belarus,B1\n" +
+				"8000 zł,Middle,3,Poland,B2\n" +
+				"1000 USD,Junior,many,Belarus,B1\n"),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				s.EXPECT().CreateDataset(gomock.Any()).Return(testDataset, nil)
+				s.EXPECT().Create(salaries)
+				s.EXPECT().ActivateDataset(testDataset.Id)
+			},
+			inputData: []*domain.Salary{
+				{DatasetId: id, FileName: "salaries.csv", Uploader: "admin", UploadedAt: testDataset.CreatedAt, Amount: 120000, AmountMin: 120000, AmountMax: 120000, Currency: "RUB", AmountUSD: 1680, LevelOfSeniority: "Middle", SeniorityRank: 2, YearsTotal: 2.5, Country: "Russia", LevelOfEnglish: "B1", RawSalary: "120000 RUS", RawYearsTotal: "2.5", RawLevelOfSeniority: "Middle"},
+				{DatasetId: id, FileName: "salaries.csv", Uploader: "admin", UploadedAt: testDataset.CreatedAt, Amount: 8000, AmountMin: 8000, AmountMax: 8000, Currency: "PLN", AmountUSD: 2000, LevelOfSeniority: "Middle", SeniorityRank: 2, YearsTotal: 3, Country: "Poland", LevelOfEnglish: "B2", RawSalary: "8000 zł", RawYearsTotal: "3", RawLevelOfSeniority: "Middle"},
+			},
+			expected: &response.SalaryUploadReport{
+				Version:        testDataset.Id.Hex(),
+				TotalRecords:   6,
+				SkippedRecords: 4,
+				Errors: []*response.RowError{
+					{Line: 3, Column: domain.ColumnSalary, Value: "1/2 USD", Code: response.RowErrorInvalidSalary, Message: "The salary amount is not a number"},
+					{Line: 4, Column: domain.ColumnSalary, Value: "1000 GBP", Code: response.RowErrorUnknownCurrency, Message: "The currency GBP can not be converted to USD"},
+					{Line: 5, Column: domain.ColumnSalary, Value: "1000 ABC", Code: response.RowErrorUnknownCurrency, Message: "The currency ABC is not an ISO 4217 code"},
+					{Line: 7, Column: domain.ColumnYearsTotal, Value: "many", Code: response.RowErrorInvalidYears, Message: "The years of experience is not a number"},
+				},
+			},
+		},
 		{
 			name: "creating report is successful",
-			file: []byte{},
-			mockBehavior: func(s *mock_ports.MockISalaryRepository, file []byte, salaries []*domain.Salary) {
-				s.EXPECT().DeleteAll()
+			file: []byte(testSalaryHeader +
+				"1000 USD,Junior,1,Belarus,B1\n" +
+				"1000 USD,Junior\n" +
+				"1000,Junior,1,Belarus,B1\n" +
+				"1000 \"USD,Junior,1,Belarus,B1\n"),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, m *mock_ports.MockIColumnMappingService, salaries []*domain.Salary) {
+				m.EXPECT().Resolve(gomock.Any()).Return(testColumnMapping, nil)
+				s.EXPECT().CreateDataset(gomock.Any()).Do(func(upload *request.SalaryUpload) {
+					assert.Equal(t, testRates.Rates, upload.Rates, "the rates of the import are saved with the dataset")
+				}).Return(testDataset, nil)
 				s.EXPECT().Create(salaries)
+				s.EXPECT().ActivateDataset(testDataset.Id)
 			},
+			inputData: []*domain.Salary{{
+				DatasetId:           id,
+				FileName:            "salaries.csv",
+				Uploader:            "admin",
+				UploadedAt:          testDataset.CreatedAt,
+				Amount:              1000,
+				AmountMin:           1000,
+				AmountMax:           1000,
+				Currency:            "USD",
+				AmountUSD:           1000,
+				LevelOfSeniority:    "Junior",
+				SeniorityRank:       1,
+				YearsTotal:          1,
+				Country:             "Belarus",
+				LevelOfEnglish:      "B1",
+				RawSalary:           "1000 USD",
+				RawYearsTotal:       "1",
+				RawLevelOfSeniority: "Junior",
+			}},
 			expected: &response.SalaryUploadReport{
-				0,
-				0,
-				[]string(nil),
+				Version:        testDataset.Id.Hex(),
+				TotalRecords:   4,
+				SkippedRecords: 3,
+				Errors: []*response.RowError{
+					{Line: 3, Column: domain.ColumnYearsTotal, Code: response.RowErrorMissingValue, Message: "The yearstotal field is empty"},
+					{Line: 3, Column: domain.ColumnCountry, Code: response.RowErrorMissingValue, Message: "The country field is empty"},
+					{Line: 3, Column: domain.ColumnLevelOfEnglish, Code: response.RowErrorMissingValue, Message: "The levelofenglish field is empty"},
+					{Line: 4, Column: domain.ColumnSalary, Value: "1000", Code: response.RowErrorInvalidSalary, Message: "The salary has no currency"},
+					{Line: 5, Code: response.RowErrorInvalidRow, Message: "bare \" in non-quoted-field"},
+				},
 			},
 		},
 	}
@@ -61,11 +376,22 @@ func TestSalaryService_Create(t *testing.T) {
 			defer c.Finish()
 
 			repo := mock_ports.NewMockISalaryRepository(c)
-			testCase.mockBehavior(repo, testCase.file, testCase.inputData)
-			newConfig := config.CurrencyConfig{CoefficientEURtoUSD: 1.1328, CoefficientRUStoUSD: 0.014}
-			service := SalaryService{repo, newConfig, logrus.New()}
+			mapping := mock_ports.NewMockIColumnMappingService(c)
+			testCase.mockBehavior(repo, mapping, testCase.inputData)
+			service := SalaryService{repo, mapping, exchangeRates(c, testRates, nil), newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			file := io.Reader(bytes.NewReader(testCase.file))
+			if testCase.readError != nil {
+				file = io.MultiReader(file, iotest.ErrReader(testCase.readError))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if testCase.cancelled {
+				cancel()
+			}
 
-			wantResult, err := service.Create(testCase.file)
+			wantResult, err := service.Create(ctx, &request.SalaryUpload{File: file, FileName: "salaries.csv", Uploader: "admin", Mode: testCase.mode, DryRun: testCase.dryRun})
 
 			if testCase.expectedError {
 				assert.Error(t, err)
@@ -77,42 +403,181 @@ func TestSalaryService_Create(t *testing.T) {
 	}
 }
 
-func TestSalaryService_GetSalariesByFilter(t *testing.T) {
-	type mockBehavior func(s *mock_ports.MockISalaryRepository, salaryFilteringCondition *request.ConditionForFilteringSalaries)
+func TestSalaryService_Create_LegacyExport(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	surveyConfig := config.SurveyConfig{Editions: []config.SurveyEdition{{Name: "legacy", Columns: legacyExportColumns}}}
+	row := make([]string, 38)
+	row[23], row[25], row[27], row[29], row[37] = "1500 USD", "B2", "Middle", "3", "Belarus"
+	file := strings.Join(legacyExportHeader(), ",") + "\n" + strings.Join(row, ",") + "\n"
+
+	repo := mock_ports.NewMockISalaryRepository(c)
+	repo.EXPECT().CreateDataset(gomock.Any()).Return(testDataset, nil)
+	repo.EXPECT().Create([]*domain.Salary{{
+		DatasetId:           id,
+		FileName:            "salaries.csv",
+		Uploader:            "admin",
+		UploadedAt:          testDataset.CreatedAt,
+		Amount:              1500,
+		AmountMin:           1500,
+		AmountMax:           1500,
+		Currency:            "USD",
+		AmountUSD:           1500,
+		LevelOfSeniority:    "Middle",
+		SeniorityRank:       2,
+		YearsTotal:          3,
+		Country:             "Belarus",
+		LevelOfEnglish:      "B2",
+		RawSalary:           "1500 USD",
+		RawYearsTotal:       "3",
+		RawLevelOfSeniority: "Middle",
+	}})
+	repo.EXPECT().ActivateDataset(testDataset.Id)
+	service := SalaryService{repo, NewColumnMappingService(surveyConfig, logrus.New()), exchangeRates(c, testRates, nil),
+		newTestSeniority(t), 2, testStatsConfig, logrus.New()}
 
+	wantResult, err := service.Create(context.Background(),
+		&request.SalaryUpload{File: strings.NewReader(file), FileName: "salaries.csv", Uploader: "admin"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &response.SalaryUploadReport{Version: testDataset.Id.Hex(), TotalRecords: 1}, wantResult)
+}
+
+func TestSalaryService_MigrateLegacySalaries(t *testing.T) {
 	testTable := []struct {
-		name                     string
-		salaryFilteringCondition *request.ConditionForFilteringSalaries
-		mockBehavior             mockBehavior
-		expected                 []*response.SalariesResponse
-		expectedError            bool
+		name           string
+		seniorityError error
+		ratesError     error
+		migrateError   error
+		expectedError  bool
 	}{
 		{
-			name: "salary filter works successfully when all field is filled",
-			salaryFilteringCondition: &request.ConditionForFilteringSalaries{
-				Salary:           "1500",
-				LevelOfSeniority: "Junior",
-				YearsTotal:       "1",
-				Country:          "Belarus",
+			name: "legacy salaries are mapped to the seniority taxonomy and converted with every current rate",
+		},
+		{
+			name:           "get error when the levels of seniority can not be migrated",
+			seniorityError: errors.New("database is unavailable"),
+			expectedError:  true,
+		},
+		{
+			name:          "get error when the exchange rates are unavailable",
+			ratesError:    errors.New("rates are unavailable"),
+			expectedError: true,
+		},
+		{
+			name:          "get error when the database is unavailable",
+			migrateError:  errors.New("database is unavailable"),
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			seniority := newTestSeniority(t)
+			repo.EXPECT().MigrateLegacySeniority(seniority.GetSeniority()).Return(testCase.seniorityError)
+			if testCase.seniorityError == nil && testCase.ratesError == nil {
+				repo.EXPECT().MigrateLegacySalaries(map[string]float64{"USD": 1, "EUR": 1.1328, "RUB": 0.014, "PLN": 0.25}).
+					Return(testCase.migrateError)
+			}
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), exchangeRates(c, testRates, testCase.ratesError),
+				seniority, 2, testStatsConfig, logrus.New()}
+
+			err := service.MigrateLegacySalaries()
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSalaryService_Rollback(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryRepository)
+
+	testTable := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expected      *domain.SalaryDataset
+		expectedError error
+	}{
+		{
+			name: "previous dataset is activated again",
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().RollbackDataset().Return(&domain.SalaryDataset{Id: id, Status: domain.DatasetStatusReady}, nil)
 			},
-			mockBehavior: func(s *mock_ports.MockISalaryRepository, salaryFilteringCondition *request.ConditionForFilteringSalaries) {
-				s.EXPECT().GetFilteredSalaries(salaryFilteringCondition).Return([]*domain.Salary{}, nil)
+			expected: &domain.SalaryDataset{Id: id, Status: domain.DatasetStatusReady},
+		},
+		{
+			name: "get error when there is no previous dataset",
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().RollbackDataset().Return(nil, domain.ErrDatasetNotFound)
 			},
-			expected: []*response.SalariesResponse(nil),
+			expectedError: domain.ErrDatasetNotFound,
 		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			testCase.mockBehavior(repo)
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), mock_ports.NewMockIExchangeRateService(c), newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			wantResult, err := service.Rollback()
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+		})
+	}
+}
+
+func TestSalaryService_ActivateVersion(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryRepository)
 
+	datasetId := primitive.ObjectID(id)
+	testTable := []struct {
+		name          string
+		id            string
+		mockBehavior  mockBehavior
+		expected      *domain.SalaryDataset
+		expectedError error
+	}{
 		{
-			name: "salary filter can not filtering when database is unavailable",
-			salaryFilteringCondition: &request.ConditionForFilteringSalaries{
-				Salary:           "1500",
-				LevelOfSeniority: "Junior",
-				YearsTotal:       "1",
-				Country:          "Belarus",
+			name: "ready dataset is activated",
+			id:   datasetId.Hex(),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				gomock.InOrder(
+					s.EXPECT().GetDataset(datasetId).Return(&domain.SalaryDataset{Id: datasetId, Status: domain.DatasetStatusReady}, nil),
+					s.EXPECT().ActivateDataset(datasetId),
+					s.EXPECT().GetDataset(datasetId).Return(&domain.SalaryDataset{Id: datasetId, Status: domain.DatasetStatusReady, Active: true}, nil),
+				)
 			},
-			mockBehavior: func(s *mock_ports.MockISalaryRepository, salaryFilteringCondition *request.ConditionForFilteringSalaries) {
-				s.EXPECT().GetFilteredSalaries(salaryFilteringCondition).Return(nil, errors.New("Error get filtered salaries "))
+			expected: &domain.SalaryDataset{Id: datasetId, Status: domain.DatasetStatusReady, Active: true},
+		},
+		{
+			name:          "get not found when the id is malformed",
+			id:            "salaries",
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: domain.ErrDatasetNotFound,
+		},
+		{
+			name: "get not found when the dataset is still being imported",
+			id:   datasetId.Hex(),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetDataset(datasetId).Return(&domain.SalaryDataset{Id: datasetId, Status: domain.DatasetStatusStaging}, nil)
 			},
-			expectedError: true,
+			expectedError: domain.ErrDatasetNotFound,
 		},
 	}
 	for _, testCase := range testTable {
@@ -121,14 +586,13 @@ func TestSalaryService_GetSalariesByFilter(t *testing.T) {
 			defer c.Finish()
 
 			repo := mock_ports.NewMockISalaryRepository(c)
-			testCase.mockBehavior(repo, testCase.salaryFilteringCondition)
-			newConfig := config.CurrencyConfig{CoefficientEURtoUSD: 1.1328, CoefficientRUStoUSD: 0.014}
-			service := SalaryService{repo, newConfig, logrus.New()}
+			testCase.mockBehavior(repo)
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), mock_ports.NewMockIExchangeRateService(c), newTestSeniority(t), 2, testStatsConfig, logrus.New()}
 
-			wantResult, err := service.GetSalariesByFilter(testCase.salaryFilteringCondition)
+			wantResult, err := service.ActivateVersion(testCase.id)
 
-			if testCase.expectedError {
-				assert.Error(t, err)
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, testCase.expected, wantResult)
@@ -136,3 +600,1158 @@ func TestSalaryService_GetSalariesByFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestSalaryService_DeleteVersion(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryRepository)
+
+	datasetId := primitive.ObjectID(id)
+	activeId := primitive.NewObjectID()
+	testTable := []struct {
+		name          string
+		id            string
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name: "inactive dataset is deleted",
+			id:   datasetId.Hex(),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetActiveDataset().Return(&domain.SalaryDataset{Id: activeId}, nil)
+				s.EXPECT().DeleteDataset(datasetId)
+			},
+		},
+		{
+			name: "dataset is deleted when no dataset is active",
+			id:   datasetId.Hex(),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetActiveDataset().Return(nil, domain.ErrDatasetNotFound)
+				s.EXPECT().DeleteDataset(datasetId)
+			},
+		},
+		{
+			name: "get conflict when the dataset is active",
+			id:   datasetId.Hex(),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetActiveDataset().Return(&domain.SalaryDataset{Id: datasetId}, nil)
+			},
+			expectedError: domain.ErrDatasetActive,
+		},
+		{
+			name: "get not found when the dataset does not exist",
+			id:   datasetId.Hex(),
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetActiveDataset().Return(&domain.SalaryDataset{Id: activeId}, nil)
+				s.EXPECT().DeleteDataset(datasetId).Return(domain.ErrDatasetNotFound)
+			},
+			expectedError: domain.ErrDatasetNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			testCase.mockBehavior(repo)
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), mock_ports.NewMockIExchangeRateService(c), newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			err := service.DeleteVersion(testCase.id)
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSalaryService_GetSalariesByFilter(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest)
+
+	testTable := []struct {
+		name          string
+		pageRequest   *request.SalaryPageRequest
+		mockBehavior  mockBehavior
+		expected      []*response.SalariesResponse
+		expectedError bool
+	}{
+		{
+			name: "salary filter works successfully when all field is filled",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				Salary:           "1500",
+				LevelOfSeniority: "Junior",
+				YearsTotal:       "1",
+				Country:          "Belarus",
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				s.EXPECT().GetFilteredSalaries(pageRequest, gomock.Any()).DoAndReturn(streamSalaries())
+			},
+			expected: []*response.SalariesResponse(nil),
+		},
+		{
+			name: "salaries are returned with the amount in USD",
+			pageRequest: &request.SalaryPageRequest{
+				ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{Country: "Latvia"},
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				s.EXPECT().GetFilteredSalaries(pageRequest, gomock.Any()).DoAndReturn(streamSalaries(
+					&domain.Salary{Amount: 3000, AmountMin: 3000, AmountMax: 3000, Currency: "EUR", AmountUSD: 3398.4, LevelOfSeniority: "Senior", YearsTotal: 5.5, Country: "Latvia"},
+				))
+			},
+			expected: []*response.SalariesResponse{
+				{Salary: "3398", LevelOfSeniority: "Senior", YearsTotal: "5.5", Country: "Latvia",
+					OriginalAmount: 3000, OriginalCurrency: "EUR", Amount: floatPointer(3398.4), Currency: "USD", Rate: floatPointer(1.1328)},
+			},
+		},
+		{
+			name: "salaries are converted to the target currency",
+			pageRequest: &request.SalaryPageRequest{
+				ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{Country: "Poland"},
+				TargetCurrency:                "eur",
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				s.EXPECT().GetFilteredSalaries(pageRequest, gomock.Any()).DoAndReturn(streamSalaries(
+					&domain.Salary{Amount: 10000, Currency: "PLN", AmountUSD: 2500, LevelOfSeniority: "Middle", YearsTotal: 3, Country: "Poland"},
+					&domain.Salary{Amount: 3000, Currency: "EUR", AmountUSD: 3398.4, LevelOfSeniority: "Senior", YearsTotal: 5, Country: "Poland"},
+				))
+			},
+			expected: []*response.SalariesResponse{
+				{Salary: "2207", LevelOfSeniority: "Middle", YearsTotal: "3", Country: "Poland",
+					OriginalAmount: 10000, OriginalCurrency: "PLN", Amount: floatPointer(2206.92), Currency: "EUR", Rate: floatPointer(0.220692)},
+				{Salary: "3000", LevelOfSeniority: "Senior", YearsTotal: "5", Country: "Poland",
+					OriginalAmount: 3000, OriginalCurrency: "EUR", Amount: floatPointer(3000), Currency: "EUR", Rate: floatPointer(1)},
+			},
+		},
+		{
+			name: "salaries that can not be converted are returned without an amount",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				RateDate: request.RateDateToday,
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				s.EXPECT().GetFilteredSalaries(gomock.Any(), gomock.Any()).DoAndReturn(streamSalaries(
+					&domain.Salary{Amount: 2000, Currency: "GBP", LevelOfSeniority: "Middle", YearsTotal: 3, Country: "Poland"},
+					&domain.Salary{Amount: 10000, Currency: "PLN", AmountUSD: 2500, LevelOfSeniority: "Middle", YearsTotal: 3, Country: "Poland"},
+				))
+			},
+			expected: []*response.SalariesResponse{
+				{LevelOfSeniority: "Middle", YearsTotal: "3", Country: "Poland", OriginalAmount: 2000, OriginalCurrency: "GBP", Currency: "USD",
+					ConversionError: "The salary 2000 GBP can not be converted to USD: there is no exchange rate for the currency"},
+				{Salary: "2500", LevelOfSeniority: "Middle", YearsTotal: "3", Country: "Poland",
+					OriginalAmount: 10000, OriginalCurrency: "PLN", Amount: floatPointer(2500), Currency: "USD", Rate: floatPointer(0.25)},
+			},
+		},
+		{
+			name: "legacy salary read without a USD amount is reported in the conversion errors",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				Country: "United Kingdom",
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				// the repository decodes the null USD amount of a legacy salary as zero
+				s.EXPECT().GetFilteredSalaries(pageRequest, gomock.Any()).DoAndReturn(streamSalaries(
+					&domain.Salary{Amount: 4000, AmountMin: 4000, AmountMax: 4000, Currency: "GBP", LevelOfSeniority: "Senior", YearsTotal: 6, Country: "United Kingdom"},
+					&domain.Salary{Amount: 3000, Currency: "USD", AmountUSD: 3000, LevelOfSeniority: "Middle", YearsTotal: 4, Country: "United Kingdom"},
+				))
+			},
+			expected: []*response.SalariesResponse{
+				{LevelOfSeniority: "Senior", YearsTotal: "6", Country: "United Kingdom", OriginalAmount: 4000, OriginalCurrency: "GBP", Currency: "USD",
+					ConversionError: "The salary 4000 GBP can not be converted to USD: the amount has no valid USD value stored at import"},
+				{Salary: "3000", LevelOfSeniority: "Middle", YearsTotal: "4", Country: "United Kingdom",
+					OriginalAmount: 3000, OriginalCurrency: "USD", Amount: floatPointer(3000), Currency: "USD", Rate: floatPointer(1)},
+			},
+		},
+		{
+			name: "get error when the target currency can not be converted",
+			pageRequest: &request.SalaryPageRequest{
+				TargetCurrency: "GBP",
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {},
+			expectedError: true,
+		},
+
+		{
+			name: "salary bounds are converted to USD before filtering",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				SalaryMin:      floatPointer(2000),
+				SalaryMax:      floatPointer(4000),
+				SalaryCurrency: "eur",
+				YearsTotalMin:  floatPointer(3),
+				YearsTotalMax:  floatPointer(5),
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				s.EXPECT().GetFilteredSalaries(&request.SalaryPageRequest{
+					ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+						SalaryMin:      floatPointer(2265.6),
+						SalaryMax:      floatPointer(4531.2),
+						SalaryCurrency: "USD",
+						YearsTotalMin:  floatPointer(3),
+						YearsTotalMax:  floatPointer(5),
+					},
+					Limit: 100,
+				}, gomock.Any()).DoAndReturn(streamSalaries())
+			},
+			expected: []*response.SalariesResponse(nil),
+		},
+		{
+			name: "salary bounds without a currency are in USD",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				SalaryMin: floatPointer(2000),
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				s.EXPECT().GetFilteredSalaries(&request.SalaryPageRequest{
+					ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+						SalaryMin:      floatPointer(2000),
+						SalaryCurrency: "USD",
+					},
+					Limit: 100,
+				}, gomock.Any()).DoAndReturn(streamSalaries())
+			},
+			expected: []*response.SalariesResponse(nil),
+		},
+		{
+			name: "today's rates are passed to the repository when they are requested",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				Country:  "Poland",
+				RateDate: request.RateDateToday,
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				s.EXPECT().GetFilteredSalaries(&request.SalaryPageRequest{
+					ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+						Country:  "Poland",
+						RateDate: "today",
+						USDRates: map[string]float64{"USD": 1, "EUR": 1.1328, "RUB": 0.014, "PLN": 0.25},
+					},
+					Limit: 100,
+				}, gomock.Any()).DoAndReturn(streamSalaries())
+			},
+			expected: []*response.SalariesResponse(nil),
+		},
+		{
+			name: "get error when the rate date is unknown",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				RateDate: "yesterday",
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+			},
+			expectedError: true,
+		},
+		{
+			name: "get error when the salary range is reversed",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				SalaryMin: floatPointer(4000),
+				SalaryMax: floatPointer(2000),
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+			},
+			expectedError: true,
+		},
+		{
+			name: "get error when the years range is reversed",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				YearsTotalMin: floatPointer(5),
+				YearsTotalMax: floatPointer(3),
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+			},
+			expectedError: true,
+		},
+		{
+			name: "levels of seniority are filtered by their canonical names and ranks",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				LevelOfSeniority:  "jr",
+				LevelsOfSeniority: &request.ValuesFilter{In: []string{"Mid", "team lead"}, NotIn: []string{"Architect"}},
+				SeniorityMin:      "regular",
+				SeniorityMax:      "Sr.",
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				s.EXPECT().GetFilteredSalaries(&request.SalaryPageRequest{
+					ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+						LevelOfSeniority:  "Junior",
+						LevelsOfSeniority: &request.ValuesFilter{In: []string{"Middle", "Lead"}, NotIn: []string{"Architect"}},
+						SeniorityMin:      "regular",
+						SeniorityMax:      "Sr.",
+						SeniorityRankMin:  floatPointer(2),
+						SeniorityRankMax:  floatPointer(3),
+					},
+					Limit: 100,
+				}, gomock.Any()).DoAndReturn(streamSalaries())
+			},
+			expected: []*response.SalariesResponse(nil),
+		},
+		{
+			name: "get error when the seniority bound is not a level of the taxonomy",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				SeniorityMin: "Architect",
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+			},
+			expectedError: true,
+		},
+		{
+			name: "get error when the seniority range is reversed",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				SeniorityMin: "Lead",
+				SeniorityMax: "Junior",
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+			},
+			expectedError: true,
+		},
+		{
+			name: "get error when the currency of the salary bounds is unknown",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				SalaryMax:      floatPointer(4000),
+				SalaryCurrency: "GBP",
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+			},
+			expectedError: true,
+		},
+		{
+			name: "page is sorted in ascending order by default",
+			pageRequest: &request.SalaryPageRequest{
+				Limit:  20,
+				Offset: 40,
+				SortBy: request.SortBySalary,
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				s.EXPECT().GetFilteredSalaries(&request.SalaryPageRequest{
+					Limit:     20,
+					Offset:    40,
+					SortBy:    "salary",
+					SortOrder: "asc",
+				}, gomock.Any()).DoAndReturn(streamSalaries(
+					&domain.Salary{Amount: 1000, Currency: "USD", AmountUSD: 1000, LevelOfSeniority: "Junior", YearsTotal: 1, Country: "Poland"},
+					&domain.Salary{Amount: 1500.5, Currency: "USD", AmountUSD: 1500.5, LevelOfSeniority: "Middle", YearsTotal: 3, Country: "Latvia"},
+				))
+			},
+			expected: []*response.SalariesResponse{
+				{Salary: "1000", LevelOfSeniority: "Junior", YearsTotal: "1", Country: "Poland",
+					OriginalAmount: 1000, OriginalCurrency: "USD", Amount: floatPointer(1000), Currency: "USD", Rate: floatPointer(1)},
+				{Salary: "1501", LevelOfSeniority: "Middle", YearsTotal: "3", Country: "Latvia",
+					OriginalAmount: 1500.5, OriginalCurrency: "USD", Amount: floatPointer(1500.5), Currency: "USD", Rate: floatPointer(1)},
+			},
+		},
+		{
+			name: "get error when the sort field is unknown",
+			pageRequest: &request.SalaryPageRequest{
+				SortBy: "levelOfEnglish",
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when the sort order is unknown",
+			pageRequest: &request.SalaryPageRequest{
+				SortBy:    request.SortByCountry,
+				SortOrder: "up",
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when the limit is too big",
+			pageRequest: &request.SalaryPageRequest{
+				Limit: 1001,
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when the offset is negative",
+			pageRequest: &request.SalaryPageRequest{
+				Offset: -1,
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {},
+			expectedError: true,
+		},
+		{
+			name: "salary filter can not filtering when database is unavailable",
+			pageRequest: &request.SalaryPageRequest{ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+				Salary:           "1500",
+				LevelOfSeniority: "Junior",
+				YearsTotal:       "1",
+				Country:          "Belarus",
+			}},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, pageRequest *request.SalaryPageRequest) {
+				s.EXPECT().GetFilteredSalaries(pageRequest, gomock.Any()).Return(errors.New("Error get filtered salaries "))
+			},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			testCase.mockBehavior(repo, testCase.pageRequest)
+			repo.EXPECT().GetActiveDataset().Return(nil, domain.ErrDatasetNotFound).AnyTimes()
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), exchangeRates(c, testRates, nil), newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			var wantResult []*response.SalariesResponse
+			err := service.GetSalariesByFilter(testCase.pageRequest, func(salary *response.SalariesResponse) error {
+				wantResult = append(wantResult, salary)
+				return nil
+			})
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+		})
+	}
+}
+
+func TestSalaryService_GetSalariesByFilter_CollectionRates(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	ratesDate := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	dataset := &domain.SalaryDataset{Id: id, Status: domain.DatasetStatusReady, RatesDate: &ratesDate,
+		Rates: map[string]float64{"EUR": 1.25, "PLN": 0.24}}
+	repo := mock_ports.NewMockISalaryRepository(c)
+	rates := mock_ports.NewMockIExchangeRateService(c)
+	repo.EXPECT().GetActiveDataset().Return(dataset, nil)
+	repo.EXPECT().GetFilteredSalaries(gomock.Any(), gomock.Any()).DoAndReturn(streamSalaries(
+		&domain.Salary{Amount: 10000, Currency: "PLN", AmountUSD: 2400, LevelOfSeniority: "Middle", YearsTotal: 3, Country: "Poland"},
+	))
+	service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), rates, newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+	var wantResult []*response.SalariesResponse
+	err := service.GetSalariesByFilter(&request.SalaryPageRequest{TargetCurrency: "EUR"}, func(salary *response.SalariesResponse) error {
+		wantResult = append(wantResult, salary)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*response.SalariesResponse{
+		{Salary: "1920", LevelOfSeniority: "Middle", YearsTotal: "3", Country: "Poland",
+			OriginalAmount: 10000, OriginalCurrency: "PLN", Amount: floatPointer(1920), Currency: "EUR", Rate: floatPointer(0.192)},
+	}, wantResult)
+}
+
+func TestSalaryService_CountSalariesByFilter(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryRepository)
+
+	testTable := []struct {
+		name          string
+		pageRequest   *request.SalaryPageRequest
+		mockBehavior  mockBehavior
+		expected      *response.SalariesPage
+		expectedError bool
+	}{
+		{
+			name: "page has the default limit and the total of the converted filter",
+			pageRequest: &request.SalaryPageRequest{
+				ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+					SalaryMin:      floatPointer(2000),
+					SalaryCurrency: "EUR",
+				},
+				SortBy:    request.SortByYearsTotal,
+				SortOrder: request.SortOrderDesc,
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetActiveDataset().Return(nil, domain.ErrDatasetNotFound)
+				s.EXPECT().CountFilteredSalaries(&request.ConditionForFilteringSalaries{
+					SalaryMin:      floatPointer(2265.6),
+					SalaryCurrency: "USD",
+				}).Return(int64(245), nil)
+			},
+			expected: &response.SalariesPage{Total: 245, Limit: 100, SortBy: "yearsTotal", SortOrder: "desc"},
+		},
+		{
+			name: "get error when the sort order has no sort field",
+			pageRequest: &request.SalaryPageRequest{
+				SortOrder: request.SortOrderDesc,
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name:        "get error when the database is unavailable",
+			pageRequest: &request.SalaryPageRequest{},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().CountFilteredSalaries(gomock.Any()).Return(int64(0), errors.New("database is unavailable"))
+			},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			testCase.mockBehavior(repo)
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), exchangeRates(c, testRates, nil), newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			wantResult, err := service.CountSalariesByFilter(testCase.pageRequest)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+		})
+	}
+}
+
+func TestSalaryService_CountSalariesByFilter_BoundsRates(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryRepository, r *mock_ports.MockIExchangeRateService)
+
+	ratesDate := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	collectionRates := &domain.ExchangeRates{Date: ratesDate, Rates: map[string]float64{"EUR": 1.05}}
+	dataset := &domain.SalaryDataset{Id: id, Status: domain.DatasetStatusReady, RatesDate: &ratesDate, Rates: collectionRates.Rates}
+	datasetWithoutRates := &domain.SalaryDataset{Id: id, Status: domain.DatasetStatusReady, RatesDate: &ratesDate}
+
+	testTable := []struct {
+		name          string
+		condition     request.ConditionForFilteringSalaries
+		mockBehavior  mockBehavior
+		expected      *request.ConditionForFilteringSalaries
+		expectedError error
+	}{
+		{
+			name:      "bounds are converted at the rates saved with the live dataset",
+			condition: request.ConditionForFilteringSalaries{SalaryMin: floatPointer(2000), SalaryCurrency: "EUR"},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, r *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetActiveDataset().Return(dataset, nil)
+			},
+			expected: &request.ConditionForFilteringSalaries{SalaryMin: floatPointer(2100), SalaryCurrency: "USD"},
+		},
+		{
+			name:      "bounds are converted at the stored rates of the rates date of a dataset without saved rates",
+			condition: request.ConditionForFilteringSalaries{SalaryMin: floatPointer(2000), SalaryCurrency: "EUR"},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, r *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetActiveDataset().Return(datasetWithoutRates, nil)
+				r.EXPECT().GetRates(&ratesDate).Return(collectionRates, nil)
+			},
+			expected: &request.ConditionForFilteringSalaries{SalaryMin: floatPointer(2100), SalaryCurrency: "USD"},
+		},
+		{
+			name: "bounds are converted at the rates saved with the requested version",
+			condition: request.ConditionForFilteringSalaries{SalaryMax: floatPointer(2000), SalaryCurrency: "EUR",
+				Version: dataset.Id.Hex()},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, r *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetDataset(dataset.Id).Return(dataset, nil)
+			},
+			expected: &request.ConditionForFilteringSalaries{SalaryMax: floatPointer(2100), SalaryCurrency: "USD",
+				Version: dataset.Id.Hex()},
+		},
+		{
+			name: "bounds are converted at today's rates when the amounts are",
+			condition: request.ConditionForFilteringSalaries{SalaryMin: floatPointer(2000), SalaryCurrency: "EUR",
+				RateDate: request.RateDateToday},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, r *mock_ports.MockIExchangeRateService) {
+				r.EXPECT().GetRates(nil).Return(testRates, nil).Times(2)
+			},
+			expected: &request.ConditionForFilteringSalaries{SalaryMin: floatPointer(2265.6), SalaryCurrency: "USD",
+				RateDate: request.RateDateToday, USDRates: map[string]float64{"USD": 1, "EUR": 1.1328, "RUB": 0.014, "PLN": 0.25}},
+		},
+		{
+			name:      "bounds are converted at the current rates when no dataset is live",
+			condition: request.ConditionForFilteringSalaries{SalaryMin: floatPointer(2000), SalaryCurrency: "EUR"},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository, r *mock_ports.MockIExchangeRateService) {
+				s.EXPECT().GetActiveDataset().Return(nil, domain.ErrDatasetNotFound)
+				r.EXPECT().GetRates(nil).Return(testRates, nil)
+			},
+			expected: &request.ConditionForFilteringSalaries{SalaryMin: floatPointer(2265.6), SalaryCurrency: "USD"},
+		},
+		{
+			name: "get not found when the requested version does not exist",
+			condition: request.ConditionForFilteringSalaries{SalaryMin: floatPointer(2000), SalaryCurrency: "EUR",
+				Version: "unknown"},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository, r *mock_ports.MockIExchangeRateService) {},
+			expectedError: domain.ErrDatasetNotFound,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			rates := mock_ports.NewMockIExchangeRateService(c)
+			testCase.mockBehavior(repo, rates)
+			if testCase.expected != nil {
+				repo.EXPECT().CountFilteredSalaries(testCase.expected).Return(int64(1), nil)
+			}
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), rates, newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			_, err := service.CountSalariesByFilter(&request.SalaryPageRequest{ConditionForFilteringSalaries: testCase.condition})
+
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSalaryService_GetSalaryStats(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryRepository)
+
+	stats := &domain.SalaryStats{Count: 5, Min: 1000, Max: 5000, Mean: 2800, Median: 2500, P10: 1400, P25: 2000, P75: 3500, P90: 4400}
+
+	testTable := []struct {
+		name          string
+		statsRequest  *request.SalaryStatsRequest
+		mockBehavior  mockBehavior
+		ratesError    error
+		expected      *response.SalaryStatsResponse
+		expectedError bool
+	}{
+		{
+			name: "statistics are returned in USD by default",
+			statsRequest: &request.SalaryStatsRequest{
+				ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{Country: "Poland"},
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryStats(&request.ConditionForFilteringSalaries{Country: "Poland"}).Return(stats, nil)
+			},
+			expected: &response.SalaryStatsResponse{
+				BaseCurrency: "USD", Currency: "USD", Rate: 1, Count: 5, Min: 1000, Max: 5000, Mean: 2800, Median: 2500, P10: 1400, P25: 2000, P75: 3500, P90: 4400,
+			},
+		},
+		{
+			name: "statistics are converted to the requested currency",
+			statsRequest: &request.SalaryStatsRequest{
+				ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{SalaryMin: floatPointer(1000), SalaryCurrency: "EUR"},
+				TargetCurrency:                "eur",
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryStats(&request.ConditionForFilteringSalaries{SalaryMin: floatPointer(1132.8), SalaryCurrency: "USD"}).Return(stats, nil)
+			},
+			expected: &response.SalaryStatsResponse{
+				BaseCurrency: "USD", Currency: "EUR", Rate: 0.882768, Count: 5, Min: 882.77, Max: 4413.84, Mean: 2471.75, Median: 2206.92, P10: 1235.88, P25: 1765.54, P75: 3089.69, P90: 3884.18,
+			},
+		},
+		{
+			name:         "empty statistics are returned when no salary matches",
+			statsRequest: &request.SalaryStatsRequest{},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryStats(&request.ConditionForFilteringSalaries{}).Return(&domain.SalaryStats{}, nil)
+			},
+			expected: &response.SalaryStatsResponse{BaseCurrency: "USD", Currency: "USD", Rate: 1},
+		},
+		{
+			name:          "get error when the currency is unknown",
+			statsRequest:  &request.SalaryStatsRequest{TargetCurrency: "GBP"},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name:          "get error when the currency is not an ISO 4217 code",
+			statsRequest:  &request.SalaryStatsRequest{TargetCurrency: "ABC"},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name:          "get error when the exchange rates are unavailable",
+			statsRequest:  &request.SalaryStatsRequest{TargetCurrency: "EUR"},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			ratesError:    errors.New("rates are unavailable"),
+			expectedError: true,
+		},
+		{
+			name:         "get error when the database is unavailable",
+			statsRequest: &request.SalaryStatsRequest{},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryStats(gomock.Any()).Return(nil, errors.New("database is unavailable"))
+			},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			testCase.mockBehavior(repo)
+			repo.EXPECT().GetActiveDataset().Return(nil, domain.ErrDatasetNotFound).AnyTimes()
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), exchangeRates(c, testRates, testCase.ratesError), newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			wantResult, err := service.GetSalaryStats(testCase.statsRequest)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+		})
+	}
+}
+
+func TestSalaryService_GetSalaryStats_CollectionRates(t *testing.T) {
+	ratesDate := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	dataset := &domain.SalaryDataset{Id: id, Status: domain.DatasetStatusReady, RatesDate: &ratesDate,
+		Rates: map[string]float64{"EUR": 1.25}}
+	stats := &domain.SalaryStats{Count: 5, Min: 1000, Max: 5000, Mean: 2800, Median: 2500, P10: 1400, P25: 2000, P75: 3500, P90: 4400}
+
+	testTable := []struct {
+		name     string
+		rateDate string
+		expected *response.SalaryStatsResponse
+	}{
+		{
+			name:     "statistics are converted at the rates saved with the live dataset",
+			rateDate: request.RateDateCollection,
+			expected: &response.SalaryStatsResponse{
+				BaseCurrency: "USD", Currency: "EUR", Rate: 0.8, Count: 5, Min: 800, Max: 4000, Mean: 2240, Median: 2000, P10: 1120, P25: 1600, P75: 2800, P90: 3520,
+			},
+		},
+		{
+			name:     "statistics are converted at today's rates when the amounts are",
+			rateDate: request.RateDateToday,
+			expected: &response.SalaryStatsResponse{
+				BaseCurrency: "USD", Currency: "EUR", Rate: 0.882768, Count: 5, Min: 882.77, Max: 4413.84, Mean: 2471.75, Median: 2206.92, P10: 1235.88, P25: 1765.54, P75: 3089.69, P90: 3884.18,
+			},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			rates := mock_ports.NewMockIExchangeRateService(c)
+			repo.EXPECT().GetActiveDataset().Return(dataset, nil).AnyTimes()
+			repo.EXPECT().GetSalaryStats(gomock.Any()).Return(stats, nil)
+			rates.EXPECT().GetRates(nil).Return(testRates, nil).AnyTimes()
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), rates, newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			wantResult, err := service.GetSalaryStats(&request.SalaryStatsRequest{
+				ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{RateDate: testCase.rateDate},
+				TargetCurrency:                "EUR",
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, wantResult)
+		})
+	}
+}
+
+func TestSalaryService_GetSalaryGroups(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryRepository)
+
+	testTable := []struct {
+		name          string
+		groupsRequest *request.SalaryGroupsRequest
+		mockBehavior  mockBehavior
+		expected      *response.SalaryGroupsResponse
+		expectedError bool
+	}{
+		{
+			name: "groups below the minimum size are suppressed",
+			groupsRequest: &request.SalaryGroupsRequest{
+				SalaryStatsRequest: request.SalaryStatsRequest{TargetCurrency: "EUR"},
+				GroupBy:            []string{domain.GroupByLevelOfSeniority, domain.GroupByCountry},
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryGroupStats(&request.ConditionForFilteringSalaries{}, []string{"levelOfSeniority", "country"}).
+					Return([]*domain.SalaryGroupStats{
+						{
+							Group:       map[string]interface{}{"levelOfSeniority": "Middle", "country": "Poland"},
+							SalaryStats: domain.SalaryStats{Count: 6, Min: 1132.8, Max: 3398.4, Mean: 2265.6, Median: 2265.6, P10: 1132.8, P25: 1699.2, P75: 2832, P90: 3398.4},
+						},
+						{
+							Group:       map[string]interface{}{"levelOfSeniority": "Senior", "country": "Latvia"},
+							SalaryStats: domain.SalaryStats{Count: 2, Min: 4000, Max: 5000, Mean: 4500, Median: 4500},
+						},
+					}, nil)
+			},
+			expected: &response.SalaryGroupsResponse{
+				GroupBy:          []string{"levelOfSeniority", "country"},
+				MinGroupSize:     5,
+				SuppressedGroups: 1,
+				Groups: []*response.SalaryGroupResponse{{
+					Group: map[string]interface{}{"levelOfSeniority": "Middle", "country": "Poland"},
+					SalaryStatsResponse: response.SalaryStatsResponse{
+						BaseCurrency: "USD", Currency: "EUR", Rate: 0.882768, Count: 6, Min: 1000, Max: 3000, Mean: 2000, Median: 2000, P10: 1000, P25: 1500, P75: 2500, P90: 3000,
+					},
+				}},
+			},
+		},
+		{
+			name: "empty groups are returned when no salary matches",
+			groupsRequest: &request.SalaryGroupsRequest{
+				GroupBy: []string{domain.GroupByYearsTotal},
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryGroupStats(&request.ConditionForFilteringSalaries{}, []string{"yearsTotal"}).Return(nil, nil)
+			},
+			expected: &response.SalaryGroupsResponse{
+				GroupBy:      []string{"yearsTotal"},
+				MinGroupSize: 5,
+				Groups:       []*response.SalaryGroupResponse{},
+			},
+		},
+		{
+			name:          "get error when no dimension is given",
+			groupsRequest: &request.SalaryGroupsRequest{},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when more than two dimensions are given",
+			groupsRequest: &request.SalaryGroupsRequest{
+				GroupBy: []string{domain.GroupByLevelOfSeniority, domain.GroupByCountry, domain.GroupByLevelOfEnglish},
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when the dimension is unknown",
+			groupsRequest: &request.SalaryGroupsRequest{
+				GroupBy: []string{"salary"},
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when the dimension is repeated",
+			groupsRequest: &request.SalaryGroupsRequest{
+				GroupBy: []string{domain.GroupByCountry, domain.GroupByCountry},
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when the database is unavailable",
+			groupsRequest: &request.SalaryGroupsRequest{
+				GroupBy: []string{domain.GroupByCountry},
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryGroupStats(gomock.Any(), gomock.Any()).Return(nil, errors.New("database is unavailable"))
+			},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			testCase.mockBehavior(repo)
+			repo.EXPECT().GetActiveDataset().Return(nil, domain.ErrDatasetNotFound).AnyTimes()
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), exchangeRates(c, testRates, nil), newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			wantResult, err := service.GetSalaryGroups(testCase.groupsRequest)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+		})
+	}
+}
+
+func TestSalaryService_GetSalaryHistogram(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryRepository)
+
+	usdStats := &domain.SalaryStats{Count: 8, Min: 500, Max: 9000, Mean: 2500, Median: 1500, P10: 600, P25: 1000, P75: 2000, P90: 4000}
+	eurStats := &domain.SalaryStats{Count: 8, Min: 1250, Max: 6250, Mean: 3125, Median: 2500, P10: 1250, P25: 1875, P75: 3125, P90: 5000}
+
+	testTable := []struct {
+		name             string
+		histogramRequest *request.SalaryHistogramRequest
+		mockBehavior     mockBehavior
+		expected         *response.SalaryHistogramResponse
+		expectedError    bool
+	}{
+		{
+			name:             "automatic bucket width leaves outliers out of the buckets",
+			histogramRequest: &request.SalaryHistogramRequest{},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryStats(&request.ConditionForFilteringSalaries{}).Return(usdStats, nil)
+				s.EXPECT().GetSalaryHistogram(&request.ConditionForFilteringSalaries{},
+					&domain.HistogramLayout{Start: 0, Width: 1000, Buckets: 4, Lower: 500, Upper: 3500}).
+					Return(&domain.SalaryHistogram{Counts: []int{1, 3, 2, 1}, Above: 1}, nil)
+			},
+			expected: &response.SalaryHistogramResponse{
+				BaseCurrency: "USD",
+				Currency:     "USD",
+				Rate:         1,
+				Count:        8,
+				BucketWidth:  1000,
+				Buckets: []*response.HistogramBucketResponse{
+					{From: 0, To: 1000, Count: 1},
+					{From: 1000, To: 2000, Count: 3},
+					{From: 2000, To: 3000, Count: 2},
+					{From: 3000, To: 4000, Count: 1},
+				},
+				Outliers: response.HistogramOutliersResponse{LowerBound: 500, UpperBound: 3500, Above: 1},
+			},
+		},
+		{
+			name: "fixed bucket width is in the requested currency",
+			histogramRequest: &request.SalaryHistogramRequest{
+				SalaryStatsRequest: request.SalaryStatsRequest{TargetCurrency: "eur"},
+				BucketWidth:        floatPointer(1000),
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryStats(&request.ConditionForFilteringSalaries{}).Return(eurStats, nil)
+				s.EXPECT().GetSalaryHistogram(&request.ConditionForFilteringSalaries{},
+					&domain.HistogramLayout{Start: 1250, Width: 1250, Buckets: 3, Lower: 1250, Upper: 5000}).
+					Return(&domain.SalaryHistogram{Counts: []int{2, 3, 2}, Above: 1}, nil)
+			},
+			expected: &response.SalaryHistogramResponse{
+				BaseCurrency: "USD",
+				Currency:     "EUR",
+				Rate:         0.8,
+				Count:        8,
+				BucketWidth:  1000,
+				Buckets: []*response.HistogramBucketResponse{
+					{From: 1000, To: 2000, Count: 2},
+					{From: 2000, To: 3000, Count: 3},
+					{From: 3000, To: 4000, Count: 2},
+				},
+				Outliers: response.HistogramOutliersResponse{LowerBound: 1000, UpperBound: 4000, Above: 1},
+			},
+		},
+		{
+			name: "number of buckets splits the requested outlier bounds",
+			histogramRequest: &request.SalaryHistogramRequest{
+				Buckets:    4,
+				OutlierMin: floatPointer(0),
+				OutlierMax: floatPointer(2000),
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryStats(&request.ConditionForFilteringSalaries{}).Return(usdStats, nil)
+				s.EXPECT().GetSalaryHistogram(&request.ConditionForFilteringSalaries{},
+					&domain.HistogramLayout{Start: 0, Width: 500, Buckets: 4, Lower: 0, Upper: 2000}).
+					Return(&domain.SalaryHistogram{Counts: []int{0, 1, 2, 3}, Above: 2}, nil)
+			},
+			expected: &response.SalaryHistogramResponse{
+				BaseCurrency: "USD",
+				Currency:     "USD",
+				Rate:         1,
+				Count:        8,
+				BucketWidth:  500,
+				Buckets: []*response.HistogramBucketResponse{
+					{From: 0, To: 500, Count: 0},
+					{From: 500, To: 1000, Count: 1},
+					{From: 1000, To: 1500, Count: 2},
+					{From: 1500, To: 2000, Count: 3},
+				},
+				Outliers: response.HistogramOutliersResponse{LowerBound: 0, UpperBound: 2000, Above: 2},
+			},
+		},
+		{
+			name:             "empty histogram is returned when no salary matches",
+			histogramRequest: &request.SalaryHistogramRequest{},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryStats(&request.ConditionForFilteringSalaries{}).Return(&domain.SalaryStats{}, nil)
+			},
+			expected: &response.SalaryHistogramResponse{
+				BaseCurrency: "USD",
+				Currency:     "USD",
+				Rate:         1,
+				Buckets:      []*response.HistogramBucketResponse{},
+			},
+		},
+		{
+			name: "get error when both bucket width and number of buckets are given",
+			histogramRequest: &request.SalaryHistogramRequest{
+				BucketWidth: floatPointer(500),
+				Buckets:     4,
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when the bucket width is not positive",
+			histogramRequest: &request.SalaryHistogramRequest{
+				BucketWidth: floatPointer(0),
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when too many buckets are requested",
+			histogramRequest: &request.SalaryHistogramRequest{
+				Buckets: 21,
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when the outlier bounds are reversed",
+			histogramRequest: &request.SalaryHistogramRequest{
+				OutlierMin: floatPointer(3000),
+				OutlierMax: floatPointer(1000),
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name: "get error when the bucket width gives too many buckets",
+			histogramRequest: &request.SalaryHistogramRequest{
+				BucketWidth: floatPointer(100),
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryStats(&request.ConditionForFilteringSalaries{}).Return(usdStats, nil)
+			},
+			expectedError: true,
+		},
+		{
+			name:             "get error when the database is unavailable",
+			histogramRequest: &request.SalaryHistogramRequest{},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetSalaryStats(gomock.Any()).Return(usdStats, nil)
+				s.EXPECT().GetSalaryHistogram(gomock.Any(), gomock.Any()).Return(nil, errors.New("database is unavailable"))
+			},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			testCase.mockBehavior(repo)
+			repo.EXPECT().GetActiveDataset().Return(nil, domain.ErrDatasetNotFound).AnyTimes()
+			rates := &domain.ExchangeRates{Rates: map[string]float64{"EUR": 1.25, "RUB": 0.014}}
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), exchangeRates(c, rates, nil), newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			wantResult, err := service.GetSalaryHistogram(testCase.histogramRequest)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+		})
+	}
+}
+
+func TestSalaryService_ExportSalaries(t *testing.T) {
+	type mockBehavior func(s *mock_ports.MockISalaryRepository)
+
+	testTable := []struct {
+		name          string
+		exportRequest *request.SalaryExportRequest
+		mockBehavior  mockBehavior
+		expected      []*response.SalaryExportRow
+		expectedError bool
+	}{
+		{
+			name: "every matching salary is exported with the original and the converted amount",
+			exportRequest: &request.SalaryExportRequest{
+				ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+					Country:   "Poland",
+					SalaryMin: floatPointer(1000),
+				},
+				TargetCurrency: "EUR",
+			},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetFilteredSalaries(&request.SalaryPageRequest{
+					ConditionForFilteringSalaries: request.ConditionForFilteringSalaries{
+						Country:        "Poland",
+						SalaryMin:      floatPointer(1000),
+						SalaryCurrency: "USD",
+					},
+				}, gomock.Any()).DoAndReturn(streamSalaries(
+					&domain.Salary{ResponseId: "17", Amount: 9000, Currency: "PLN", AmountUSD: 2500, LevelOfSeniority: "Middle", YearsTotal: 3.5, Country: "Poland", LevelOfEnglish: "B2"},
+					&domain.Salary{ResponseId: "18", Amount: 1800, Currency: "EUR", AmountUSD: 2250, LevelOfSeniority: "Junior", YearsTotal: 1, Country: "Poland", LevelOfEnglish: "B1"},
+				))
+			},
+			expected: []*response.SalaryExportRow{
+				{ResponseId: "17", LevelOfSeniority: "Middle", YearsTotal: 3.5, Country: "Poland", LevelOfEnglish: "B2", OriginalAmount: 9000, OriginalCurrency: "PLN", Amount: floatPointer(2000), Currency: "EUR"},
+				{ResponseId: "18", LevelOfSeniority: "Junior", YearsTotal: 1, Country: "Poland", LevelOfEnglish: "B1", OriginalAmount: 1800, OriginalCurrency: "EUR", Amount: floatPointer(1800), Currency: "EUR"},
+			},
+		},
+		{
+			name:          "salary without a stored USD amount is exported with the reason it can not be converted",
+			exportRequest: &request.SalaryExportRequest{},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetFilteredSalaries(gomock.Any(), gomock.Any()).DoAndReturn(streamSalaries(
+					&domain.Salary{ResponseId: "19", Amount: 9000, Currency: "PLN", LevelOfSeniority: "Middle", YearsTotal: 3, Country: "Poland", LevelOfEnglish: "B2"},
+				))
+			},
+			expected: []*response.SalaryExportRow{
+				{ResponseId: "19", LevelOfSeniority: "Middle", YearsTotal: 3, Country: "Poland", LevelOfEnglish: "B2", OriginalAmount: 9000, OriginalCurrency: "PLN", Currency: "USD",
+					ConversionError: "The salary 9000 PLN can not be converted to USD: the amount has no valid USD value stored at import"},
+			},
+		},
+		{
+			name: "get error when the export currency is unknown",
+			exportRequest: &request.SalaryExportRequest{
+				TargetCurrency: "GBP",
+			},
+			mockBehavior:  func(s *mock_ports.MockISalaryRepository) {},
+			expectedError: true,
+		},
+		{
+			name:          "get error when the database is unavailable",
+			exportRequest: &request.SalaryExportRequest{},
+			mockBehavior: func(s *mock_ports.MockISalaryRepository) {
+				s.EXPECT().GetFilteredSalaries(gomock.Any(), gomock.Any()).Return(errors.New("database is unavailable"))
+			},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_ports.NewMockISalaryRepository(c)
+			testCase.mockBehavior(repo)
+			repo.EXPECT().GetActiveDataset().Return(nil, domain.ErrDatasetNotFound).AnyTimes()
+			rates := &domain.ExchangeRates{Rates: map[string]float64{"EUR": 1.25, "RUB": 0.014}}
+			service := SalaryService{repo, mock_ports.NewMockIColumnMappingService(c), exchangeRates(c, rates, nil), newTestSeniority(t), 2, testStatsConfig, logrus.New()}
+
+			var wantResult []*response.SalaryExportRow
+			err := service.ExportSalaries(testCase.exportRequest, func(row *response.SalaryExportRow) error {
+				wantResult = append(wantResult, row)
+				return nil
+			})
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, wantResult)
+			}
+		})
+	}
+}
+
+// exchangeRates returns the rates or the error every time the service asks for the rates of any date
+func exchangeRates(c *gomock.Controller, rates *domain.ExchangeRates, err error) *mock_ports.MockIExchangeRateService {
+	service := mock_ports.NewMockIExchangeRateService(c)
+	if err != nil {
+		rates = nil
+	}
+	service.EXPECT().GetRates(gomock.Any()).Return(rates, err).AnyTimes()
+	return service
+}
+
+// streamSalaries passes the salaries to the callback of GetFilteredSalaries like the repository reads them
+func streamSalaries(salaries ...*domain.Salary) func(*request.SalaryPageRequest, func(*domain.Salary) error) error {
+	return func(_ *request.SalaryPageRequest, each func(*domain.Salary) error) error {
+		for _, salary := range salaries {
+			if err := each(salary); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func floatPointer(value float64) *float64 {
+	return &value
+}
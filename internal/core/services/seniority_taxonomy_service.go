@@ -0,0 +1,40 @@
+package services
+
+import (
+	"github.com/inkoba/app_for_HR/internal/config"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"github.com/sirupsen/logrus"
+)
+
+type SeniorityTaxonomyService struct {
+	taxonomy *domain.SeniorityTaxonomy
+	logger   *logrus.Logger
+}
+
+var _ ports.ISeniorityTaxonomyService = (*SeniorityTaxonomyService)(nil)
+
+// NewSeniorityTaxonomyService ranks the configured levels of seniority in the order they are listed
+func NewSeniorityTaxonomyService(taxonomyConfig config.TaxonomyConfig, logger *logrus.Logger) (*SeniorityTaxonomyService, error) {
+	levels := make([]*domain.SeniorityLevel, 0, len(taxonomyConfig.Seniority))
+	for _, level := range taxonomyConfig.Seniority {
+		levels = append(levels, &domain.SeniorityLevel{Name: level.Name, Aliases: level.Aliases})
+	}
+
+	taxonomy, err := domain.NewSeniorityTaxonomy(levels)
+	if err != nil {
+		return nil, err
+	}
+	if len(taxonomy.Levels) == 0 {
+		logger.Warn("No seniority levels are configured, levels of seniority are kept as they are written")
+	}
+
+	return &SeniorityTaxonomyService{
+		taxonomy,
+		logger,
+	}, nil
+}
+
+func (ts SeniorityTaxonomyService) GetSeniority() *domain.SeniorityTaxonomy {
+	return ts.taxonomy
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"github.com/inkoba/app_for_HR/internal/config"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewSeniorityTaxonomyService(t *testing.T) {
+	testTable := []struct {
+		name           string
+		taxonomyConfig config.TaxonomyConfig
+		expected       []*domain.SeniorityLevel
+		expectedError  bool
+	}{
+		{
+			name:           "levels are ranked in the configured order",
+			taxonomyConfig: testTaxonomyConfig,
+			expected: []*domain.SeniorityLevel{
+				{Name: "Junior", Rank: 1, Aliases: []string{"Jr"}},
+				{Name: "Middle", Rank: 2, Aliases: []string{"Mid", "Regular"}},
+				{Name: "Senior", Rank: 3, Aliases: []string{"Sr"}},
+				{Name: "Lead", Rank: 4, Aliases: []string{"Team lead"}},
+			},
+		},
+		{
+			name: "aliases repeating the name of their level are dropped",
+			taxonomyConfig: config.TaxonomyConfig{Seniority: []config.SeniorityLevelConfig{
+				{Name: " Senior ", Aliases: []string{"senior", "Sr", "SR.", ""}},
+			}},
+			expected: []*domain.SeniorityLevel{
+				{Name: "Senior", Rank: 1, Aliases: []string{"Sr"}},
+			},
+		},
+		{
+			name:           "no levels are configured",
+			taxonomyConfig: config.TaxonomyConfig{},
+			expected:       []*domain.SeniorityLevel{},
+		},
+		{
+			name: "get error when an alias belongs to two levels",
+			taxonomyConfig: config.TaxonomyConfig{Seniority: []config.SeniorityLevelConfig{
+				{Name: "Lead", Aliases: []string{"Tech lead"}},
+				{Name: "Principal", Aliases: []string{"tech-lead"}},
+			}},
+			expectedError: true,
+		},
+		{
+			name: "get error when a level has no name",
+			taxonomyConfig: config.TaxonomyConfig{Seniority: []config.SeniorityLevelConfig{
+				{Name: " ", Aliases: []string{"Jr"}},
+			}},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			service, err := NewSeniorityTaxonomyService(testCase.taxonomyConfig, logrus.New())
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, service.GetSeniority().Levels)
+			}
+		})
+	}
+}
+
+func TestSeniorityTaxonomy_Level(t *testing.T) {
+	testTable := []struct {
+		name         string
+		value        string
+		expected     string
+		expectedRank int
+	}{
+		{name: "level is found by its name", value: "Senior", expected: "Senior", expectedRank: 3},
+		{name: "level is found by an alias regardless of case", value: "JR", expected: "Junior", expectedRank: 1},
+		{name: "dots and spaces around an alias are ignored", value: " Sr. ", expected: "Senior", expectedRank: 3},
+		{name: "dashes and repeated spaces are read as one space", value: "team-lead", expected: "Lead", expectedRank: 4},
+		{name: "value of no level is kept trimmed", value: " Architect ", expected: "Architect"},
+	}
+	taxonomy := newTestSeniority(t).GetSeniority()
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			level, ok := taxonomy.Level(testCase.value)
+
+			assert.Equal(t, testCase.expected, taxonomy.Canonical(testCase.value))
+			assert.Equal(t, testCase.expectedRank != 0, ok)
+			if ok {
+				assert.Equal(t, testCase.expectedRank, level.Rank)
+			}
+		})
+	}
+}
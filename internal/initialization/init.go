@@ -5,6 +5,7 @@ import (
 	"github.com/inkoba/app_for_HR/internal/config"
 	"github.com/inkoba/app_for_HR/internal/core/handlers"
 	"github.com/inkoba/app_for_HR/internal/core/services"
+	"github.com/inkoba/app_for_HR/internal/providers"
 	"github.com/inkoba/app_for_HR/internal/repositories"
 	"github.com/sirupsen/logrus"
 	"net/http"
@@ -18,19 +19,38 @@ func Initialize(c config.Config, logger *logrus.Logger, errs chan error) {
 	healthRepository := repositories.NewHealthRepository(mongoConfig, logger)
 	userRepository := repositories.NewUserRepository(mongoConfig, logger)
 	salaryRepository := repositories.NewSalaryRepository(mongoConfig, logger)
+	exchangeRateRepository := repositories.NewExchangeRateRepository(mongoConfig, logger)
+
+	exchangeRateProvider, err := providers.NewExchangeRateProvider(c.CurrencyConfig, logger)
+	if err != nil {
+		logger.Fatal(err)
+	}
 
 	appCrypto := services.NewHashPassword(logger)
 	userService := services.NewUserService(userRepository, logger, appCrypto)
 	authService := services.NewAuthService(userRepository, logger, appCrypto)
 	healthService := services.NewHealthService(healthRepository, logger)
-	salaryService := services.NewSalaryService(c.CurrencyConfig, salaryRepository, logger)
+	columnMappingService := services.NewColumnMappingService(c.SurveyConfig, logger)
+	seniorityTaxonomyService, err := services.NewSeniorityTaxonomyService(c.TaxonomyConfig, logger)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	exchangeRateService := services.NewExchangeRateService(exchangeRateRepository, exchangeRateProvider, providers.NewECBRatesParser(), logger)
+	salaryService := services.NewSalaryService(exchangeRateService, c.ImportConfig, c.StatsConfig, salaryRepository, columnMappingService, seniorityTaxonomyService, logger)
+	importJobService := services.NewImportJobService(c.ImportConfig, salaryService, logger)
+	if err := salaryService.MigrateLegacySalaries(); err != nil {
+		logger.Error(err)
+	}
 
 	userHandler := handlers.NewUserHandler(userService, logger)
 	authHandler := handlers.NewAuthHandler(authService, userService, logger)
 	healthHandler := handlers.NewHealthHandler(healthService, logger)
-	salaryHandler := handlers.NewSalaryHandler(salaryService, logger)
+	salaryHandler := handlers.NewSalaryHandler(salaryService, importJobService, logger)
+	importJobHandler := handlers.NewImportJobHandler(importJobService, logger)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService, logger)
 	middlewareHandler := handlers.NewMiddlewareHandler(logger)
 	filterHandler := handlers.NewSalaryFilterHandler(salaryService, logger)
+	taxonomyHandler := handlers.NewTaxonomyHandler(seniorityTaxonomyService, logger)
 
 	router := mux.NewRouter()
 	router.Use(middlewareHandler.LogURL)
@@ -45,9 +65,28 @@ func Initialize(c config.Config, logger *logrus.Logger, errs chan error) {
 	subRouter.HandleFunc("/users/{id:[a-zA-Z0-9]*}", userHandler.Delete).Methods("DELETE")
 
 	router.HandleFunc("/api/salaries", salaryHandler.UploadFile).Methods("POST")
+	subRouter.HandleFunc("/imports/{id:[a-zA-Z0-9]*}", importJobHandler.Get).Methods("GET")
+	subRouter.HandleFunc("/imports/{id:[a-zA-Z0-9]*}/cancel", importJobHandler.Cancel).Methods("POST")
+	subRouter.HandleFunc("/imports/{id:[a-zA-Z0-9]*}/errors", importJobHandler.GetErrors).Methods("GET")
+	subRouter.HandleFunc("/salaries/rollback", salaryHandler.Rollback).Methods("POST")
+	subRouter.HandleFunc("/salaries/versions", salaryHandler.GetAllVersions).Methods("GET")
+	subRouter.HandleFunc("/salaries/versions/{id:[a-zA-Z0-9]*}/activate", salaryHandler.ActivateVersion).Methods("POST")
+	subRouter.HandleFunc("/salaries/versions/{id:[a-zA-Z0-9]*}", salaryHandler.DeleteVersion).Methods("DELETE")
+	subRouter.HandleFunc("/exchange-rates", exchangeRateHandler.Import).Methods("POST")
+	subRouter.HandleFunc("/exchange-rates", exchangeRateHandler.Get).Methods("GET")
+	subRouter.HandleFunc("/currency-rates", exchangeRateHandler.GetCurrencyRates).Methods("GET")
+	subRouter.HandleFunc("/currency-rates", exchangeRateHandler.CreateCurrencyRate).Methods("POST")
+	subRouter.HandleFunc("/currency-rates/changes", exchangeRateHandler.GetCurrencyRateChanges).Methods("GET")
+	subRouter.HandleFunc("/currency-rates/{currency}", exchangeRateHandler.UpdateCurrencyRate).Methods("PUT")
+	subRouter.HandleFunc("/currency-rates/{currency}", exchangeRateHandler.DeleteCurrencyRate).Methods("DELETE")
 
 	router.HandleFunc("/api/login", authHandler.Login).Methods("POST")
 	router.HandleFunc("/api/filter", filterHandler.Filter).Methods("POST")
+	router.HandleFunc("/api/salaries/stats", filterHandler.Stats).Methods("POST")
+	router.HandleFunc("/api/salaries/groups", filterHandler.Groups).Methods("POST")
+	router.HandleFunc("/api/salaries/histogram", filterHandler.Histogram).Methods("POST")
+	router.HandleFunc("/api/salaries/export", filterHandler.Export).Methods("POST")
+	router.HandleFunc("/api/taxonomy/seniority", taxonomyHandler.Seniority).Methods("GET")
 	http.Handle("/", router)
 
 	go func() {
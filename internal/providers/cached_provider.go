@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRatesTTL = time.Hour
+	dateLayout      = "2006-01-02"
+)
+
+// CachedExchangeRateProvider keeps the rates of another provider for the TTL.
+// When a refresh fails the previous rates are used until the next attempt.
+type CachedExchangeRateProvider struct {
+	provider  ports.IExchangeRateProvider
+	ttl       time.Duration
+	logger    *logrus.Logger
+	mutex     *sync.Mutex
+	rates     *domain.ExchangeRates
+	fetchedAt time.Time
+}
+
+var _ ports.IExchangeRateProvider = (*CachedExchangeRateProvider)(nil)
+
+func NewCachedExchangeRateProvider(provider ports.IExchangeRateProvider, ttl time.Duration, logger *logrus.Logger) *CachedExchangeRateProvider {
+	if ttl <= 0 {
+		ttl = defaultRatesTTL
+	}
+	return &CachedExchangeRateProvider{provider, ttl, logger, &sync.Mutex{}, nil, time.Time{}}
+}
+
+func (cp *CachedExchangeRateProvider) GetRates() (*domain.ExchangeRates, error) {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+
+	if cp.rates != nil && time.Since(cp.fetchedAt) < cp.ttl {
+		return cp.rates, nil
+	}
+
+	rates, err := cp.provider.GetRates()
+	if err != nil {
+		if cp.rates == nil {
+			return nil, err
+		}
+		cp.logger.Error("Error refreshing exchange rates, using the rates of ", cp.rates.Date.Format(dateLayout), ": ", err)
+		cp.fetchedAt = time.Now()
+		return cp.rates, nil
+	}
+
+	cp.rates = rates
+	cp.fetchedAt = time.Now()
+	return rates, nil
+}
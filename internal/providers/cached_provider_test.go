@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	mock_ports "github.com/inkoba/app_for_HR/internal/core/ports/mocks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCachedExchangeRateProvider_GetRates(t *testing.T) {
+	first := &domain.ExchangeRates{Rates: map[string]float64{"EUR": 1.1}}
+	second := &domain.ExchangeRates{Rates: map[string]float64{"EUR": 1.2}}
+
+	testTable := []struct {
+		name          string
+		mockBehavior  func(p *mock_ports.MockIExchangeRateProvider)
+		expired       bool
+		expected      []*domain.ExchangeRates
+		expectedError bool
+	}{
+		{
+			name: "rates are fetched once within the TTL",
+			mockBehavior: func(p *mock_ports.MockIExchangeRateProvider) {
+				p.EXPECT().GetRates().Return(first, nil)
+			},
+			expected: []*domain.ExchangeRates{first, first},
+		},
+		{
+			name: "rates are fetched again after the TTL",
+			mockBehavior: func(p *mock_ports.MockIExchangeRateProvider) {
+				gomock.InOrder(
+					p.EXPECT().GetRates().Return(first, nil),
+					p.EXPECT().GetRates().Return(second, nil),
+				)
+			},
+			expired:  true,
+			expected: []*domain.ExchangeRates{first, second},
+		},
+		{
+			name: "previous rates are used when the refresh fails",
+			mockBehavior: func(p *mock_ports.MockIExchangeRateProvider) {
+				gomock.InOrder(
+					p.EXPECT().GetRates().Return(first, nil),
+					p.EXPECT().GetRates().Return(nil, errors.New("rates are unavailable")),
+				)
+			},
+			expired:  true,
+			expected: []*domain.ExchangeRates{first, first},
+		},
+		{
+			name: "get error when the rates were never fetched",
+			mockBehavior: func(p *mock_ports.MockIExchangeRateProvider) {
+				p.EXPECT().GetRates().Return(nil, errors.New("rates are unavailable"))
+			},
+			expected:      []*domain.ExchangeRates{nil},
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			provider := mock_ports.NewMockIExchangeRateProvider(c)
+			testCase.mockBehavior(provider)
+			cached := NewCachedExchangeRateProvider(provider, time.Hour, logrus.New())
+
+			for i, expected := range testCase.expected {
+				if i > 0 && testCase.expired {
+					cached.fetchedAt = cached.fetchedAt.Add(-2 * time.Hour)
+				}
+				wantResult, err := cached.GetRates()
+
+				if testCase.expectedError {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+					assert.Equal(t, expected, wantResult)
+				}
+			}
+		})
+	}
+}
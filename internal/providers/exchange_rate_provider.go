@@ -0,0 +1,39 @@
+package providers
+
+import (
+	"fmt"
+	"github.com/inkoba/app_for_HR/internal/config"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+const (
+	RateProviderStatic = "static"
+	RateProviderFile   = "file"
+	RateProviderHTTP   = "http"
+)
+
+// NewExchangeRateProvider builds the provider selected in the config, file and http rates are cached for the configured TTL
+func NewExchangeRateProvider(c config.CurrencyConfig, logger *logrus.Logger) (ports.IExchangeRateProvider, error) {
+	switch strings.ToLower(c.RateProvider) {
+	case "", RateProviderStatic:
+		return NewStaticExchangeRateProvider(c), nil
+	case RateProviderFile:
+		format, err := RatesFormat(c.RatesFormat, c.RatesFile)
+		if err != nil {
+			return nil, err
+		}
+		provider := NewFileExchangeRateProvider(c.RatesFile, format)
+		return NewCachedExchangeRateProvider(provider, c.RatesTTL, logger), nil
+	case RateProviderHTTP:
+		format, err := RatesFormat(c.RatesFormat, c.RatesURL)
+		if err != nil {
+			return nil, err
+		}
+		provider := NewHTTPExchangeRateProvider(c.RatesURL, format, c.RatesTimeout)
+		return NewCachedExchangeRateProvider(provider, c.RatesTTL, logger), nil
+	}
+	return nil, fmt.Errorf("Unknown exchange rate provider %q, use one of: %s, %s, %s",
+		c.RateProvider, RateProviderStatic, RateProviderFile, RateProviderHTTP)
+}
@@ -0,0 +1,214 @@
+package providers
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	csvDateLayout = "02 January 2006"
+
+	currencyUSD = "USD"
+	currencyEUR = "EUR"
+)
+
+var errNoRates = errors.New("The exchange rates file has no rates")
+
+// ECBRatesParser reads the ECB reference rates files imported by admins
+type ECBRatesParser struct{}
+
+var _ ports.IExchangeRatesParser = (*ECBRatesParser)(nil)
+
+func NewECBRatesParser() *ECBRatesParser {
+	return &ECBRatesParser{}
+}
+
+func (ep ECBRatesParser) Format(format string, fileName string) (string, error) {
+	return RatesFormat(format, fileName)
+}
+
+func (ep ECBRatesParser) Parse(reader io.Reader, format string) ([]*domain.ExchangeRates, error) {
+	return ParseExchangeRates(reader, format)
+}
+
+// ecbEnvelope is the eurofxref XML, the cubes of a day are nested in the cube with the time attribute
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseExchangeRates reads every day of an ECB reference rates file in the XML or CSV format.
+// ECB rates are units of the currency per euro, they are converted to USD per unit. The newest day comes first.
+func ParseExchangeRates(reader io.Reader, format string) ([]*domain.ExchangeRates, error) {
+	var days []*domain.ExchangeRates
+	var err error
+	switch format {
+	case request.RatesFormatXML:
+		days, err = parseECBXML(reader)
+	case request.RatesFormatCSV:
+		days, err = parseECBCSV(reader)
+	default:
+		return nil, fmt.Errorf("Unknown exchange rates format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return nil, errNoRates
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.After(days[j].Date)
+	})
+	return days, nil
+}
+
+// RatesFormat returns the format or the one given by the extension of the file name or URL, XML by default
+func RatesFormat(format string, fileName string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if len(format) == 0 {
+		if strings.EqualFold(path.Ext(strings.SplitN(fileName, "?", 2)[0]), "."+request.RatesFormatCSV) {
+			return request.RatesFormatCSV, nil
+		}
+		return request.RatesFormatXML, nil
+	}
+	if format != request.RatesFormatXML && format != request.RatesFormatCSV {
+		return "", domain.NewValidationError("Exchange rates format %q is not supported, use %s or %s",
+			format, request.RatesFormatXML, request.RatesFormatCSV)
+	}
+	return format, nil
+}
+
+func parseECBXML(reader io.Reader) ([]*domain.ExchangeRates, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(reader).Decode(&envelope); err != nil {
+		return nil, err
+	}
+
+	days := make([]*domain.ExchangeRates, 0, len(envelope.Days))
+	for _, day := range envelope.Days {
+		date, err := time.Parse(dateLayout, day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("The exchange rates date %q is not valid", day.Time)
+		}
+		perEUR := make(map[string]float64, len(day.Rates))
+		for _, rate := range day.Rates {
+			value, err := parseRate(rate.Rate)
+			if err != nil {
+				return nil, fmt.Errorf("The %s rate of %s is not a number", rate.Currency, day.Time)
+			}
+			perEUR[strings.ToUpper(rate.Currency)] = value
+		}
+		rates, err := usdRates(date, perEUR)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, rates)
+	}
+	return days, nil
+}
+
+// parseECBCSV reads the eurofxref CSV files, the daily one writes dates like "05 January 2024",
+// the history one like "2024-01-05". Missing rates are written as N/A and lines end with a comma.
+func parseECBCSV(reader io.Reader) ([]*domain.ExchangeRates, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, errNoRates
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(header) < 2 || !strings.EqualFold(strings.TrimSpace(header[0]), "Date") {
+		return nil, errors.New("The exchange rates file must start with a Date column")
+	}
+
+	var days []*domain.ExchangeRates
+	for {
+		line, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		date, err := parseCSVDate(line[0])
+		if err != nil {
+			return nil, err
+		}
+		perEUR := make(map[string]float64, len(header))
+		for i := 1; i < len(line) && i < len(header); i++ {
+			currency := strings.ToUpper(strings.TrimSpace(header[i]))
+			value := strings.TrimSpace(line[i])
+			if len(currency) == 0 || len(value) == 0 || strings.EqualFold(value, "N/A") {
+				continue
+			}
+			rate, err := parseRate(value)
+			if err != nil {
+				return nil, fmt.Errorf("The %s rate of %s is not a number", currency, strings.TrimSpace(line[0]))
+			}
+			perEUR[currency] = rate
+		}
+		rates, err := usdRates(date, perEUR)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, rates)
+	}
+	return days, nil
+}
+
+func parseCSVDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{dateLayout, csvDateLayout} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("The exchange rates date %q is not valid", value)
+}
+
+func parseRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || rate <= 0 {
+		return 0, errors.New("The rate is not a positive number")
+	}
+	return rate, nil
+}
+
+// usdRates converts the euro based rates of the day to the USD value of one unit of every currency
+func usdRates(date time.Time, perEUR map[string]float64) (*domain.ExchangeRates, error) {
+	usdPerEUR, ok := perEUR[currencyUSD]
+	if !ok {
+		return nil, fmt.Errorf("The exchange rates of %s have no USD rate", date.Format(dateLayout))
+	}
+
+	rates := make(map[string]float64, len(perEUR))
+	for currency, rate := range perEUR {
+		if currency != currencyUSD {
+			rates[currency] = usdPerEUR / rate
+		}
+	}
+	rates[currencyEUR] = usdPerEUR
+	return &domain.ExchangeRates{Date: date, Rates: rates}, nil
+}
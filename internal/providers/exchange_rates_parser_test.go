@@ -0,0 +1,154 @@
+package providers
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testECBXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2024-01-04">
+			<Cube currency="USD" rate="1.0944"/>
+			<Cube currency="PLN" rate="4.3515"/>
+		</Cube>
+		<Cube time="2024-01-05">
+			<Cube currency="USD" rate="1.0921"/>
+			<Cube currency="PLN" rate="4.3655"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestParseExchangeRates(t *testing.T) {
+	testTable := []struct {
+		name          string
+		format        string
+		input         string
+		expected      []*domain.ExchangeRates
+		expectedError bool
+	}{
+		{
+			name:   "XML rates are converted to USD with the newest day first",
+			format: request.RatesFormatXML,
+			input:  testECBXML,
+			expected: []*domain.ExchangeRates{
+				{Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Rates: map[string]float64{"EUR": 1.0921, "PLN": 0.250166}},
+				{Date: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), Rates: map[string]float64{"EUR": 1.0944, "PLN": 0.251499}},
+			},
+		},
+		{
+			name:   "daily CSV rates with trailing commas",
+			format: request.RatesFormatCSV,
+			input:  "Date, USD, JPY, PLN, \n05 January 2024, 1.0921, 157.21, 4.3655, \n",
+			expected: []*domain.ExchangeRates{
+				{Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Rates: map[string]float64{"EUR": 1.0921, "JPY": 0.006947, "PLN": 0.250166}},
+			},
+		},
+		{
+			name:   "history CSV rates skip missing values",
+			format: request.RatesFormatCSV,
+			input:  "Date,USD,RUB,\n2022-03-01,1.1139,N/A,\n2022-02-28,1.1240,117.2010,\n",
+			expected: []*domain.ExchangeRates{
+				{Date: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), Rates: map[string]float64{"EUR": 1.1139}},
+				{Date: time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC), Rates: map[string]float64{"EUR": 1.124, "RUB": 0.009590}},
+			},
+		},
+		{
+			name:          "get error when there is no USD rate",
+			format:        request.RatesFormatCSV,
+			input:         "Date,PLN\n2024-01-05,4.3655\n",
+			expectedError: true,
+		},
+		{
+			name:          "get error when a rate is not a number",
+			format:        request.RatesFormatCSV,
+			input:         "Date,USD\n2024-01-05,abc\n",
+			expectedError: true,
+		},
+		{
+			name:          "get error when the file has no days",
+			format:        request.RatesFormatXML,
+			input:         `<Envelope><Cube></Cube></Envelope>`,
+			expectedError: true,
+		},
+		{
+			name:          "get error when the format is unknown",
+			format:        "json",
+			input:         "{}",
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			wantResult, err := ParseExchangeRates(strings.NewReader(testCase.input), testCase.format)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, wantResult, len(testCase.expected))
+			for i, expected := range testCase.expected {
+				assert.Equal(t, expected.Date, wantResult[i].Date)
+				assert.Len(t, wantResult[i].Rates, len(expected.Rates))
+				for currency, rate := range expected.Rates {
+					assert.InDelta(t, rate, wantResult[i].Rates[currency], 0.000001, currency)
+				}
+			}
+		})
+	}
+}
+
+func TestRatesFormat(t *testing.T) {
+	testTable := []struct {
+		name          string
+		format        string
+		fileName      string
+		expected      string
+		expectedError bool
+	}{
+		{
+			name:     "CSV is given by the file extension",
+			fileName: "eurofxref-hist.CSV",
+			expected: request.RatesFormatCSV,
+		},
+		{
+			name:     "query of the URL is ignored",
+			fileName: "https://example.com/eurofxref.csv?download=1",
+			expected: request.RatesFormatCSV,
+		},
+		{
+			name:     "XML is the default",
+			fileName: "rates.txt",
+			expected: request.RatesFormatXML,
+		},
+		{
+			name:     "format is used before the file extension",
+			format:   " XML ",
+			fileName: "rates.csv",
+			expected: request.RatesFormatXML,
+		},
+		{
+			name:          "get error when the format is unknown",
+			format:        "json",
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			wantResult, err := RatesFormat(testCase.format, testCase.fileName)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, wantResult)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package providers
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"os"
+)
+
+// FileExchangeRateProvider reads the latest rates of an ECB-style XML or CSV file
+type FileExchangeRateProvider struct {
+	path   string
+	format string
+}
+
+var _ ports.IExchangeRateProvider = (*FileExchangeRateProvider)(nil)
+
+func NewFileExchangeRateProvider(path string, format string) *FileExchangeRateProvider {
+	return &FileExchangeRateProvider{path, format}
+}
+
+func (fp FileExchangeRateProvider) GetRates() (*domain.ExchangeRates, error) {
+	file, err := os.Open(fp.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	days, err := ParseExchangeRates(file, fp.format)
+	if err != nil {
+		return nil, err
+	}
+	return days[0], nil
+}
@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileExchangeRateProvider_GetRates(t *testing.T) {
+	testTable := []struct {
+		name          string
+		fileName      string
+		content       string
+		format        string
+		missing       bool
+		expectedDate  time.Time
+		expectedRates map[string]float64
+		expectedError bool
+	}{
+		{
+			name:          "rates of the newest day are returned",
+			fileName:      "eurofxref.xml",
+			content:       testECBXML,
+			format:        request.RatesFormatXML,
+			expectedDate:  time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+			expectedRates: map[string]float64{"EUR": 1.0921, "PLN": 0.250166},
+		},
+		{
+			name:          "CSV rates are read",
+			fileName:      "eurofxref.csv",
+			content:       "Date,USD,PLN,\n2024-01-05,1.0921,4.3655,\n",
+			format:        request.RatesFormatCSV,
+			expectedDate:  time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+			expectedRates: map[string]float64{"EUR": 1.0921, "PLN": 0.250166},
+		},
+		{
+			name:          "get error when the file is malformed",
+			fileName:      "eurofxref.xml",
+			content:       "<Envelope><Cube>",
+			format:        request.RatesFormatXML,
+			expectedError: true,
+		},
+		{
+			name:          "get error when the file is missing",
+			fileName:      "eurofxref.xml",
+			format:        request.RatesFormatXML,
+			missing:       true,
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), testCase.fileName)
+			if !testCase.missing {
+				assert.NoError(t, os.WriteFile(path, []byte(testCase.content), 0600))
+			}
+			provider := NewFileExchangeRateProvider(path, testCase.format)
+
+			wantResult, err := provider.GetRates()
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedDate, wantResult.Date)
+			assert.Len(t, wantResult.Rates, len(testCase.expectedRates))
+			for currency, rate := range testCase.expectedRates {
+				assert.InDelta(t, rate, wantResult.Rates[currency], 0.000001, currency)
+			}
+		})
+	}
+}
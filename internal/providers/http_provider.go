@@ -0,0 +1,45 @@
+package providers
+
+import (
+	"fmt"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"net/http"
+	"time"
+)
+
+const defaultRatesTimeout = 10 * time.Second
+
+// HTTPExchangeRateProvider downloads the latest rates in the ECB XML or CSV format
+type HTTPExchangeRateProvider struct {
+	url    string
+	format string
+	client *http.Client
+}
+
+var _ ports.IExchangeRateProvider = (*HTTPExchangeRateProvider)(nil)
+
+func NewHTTPExchangeRateProvider(url string, format string, timeout time.Duration) *HTTPExchangeRateProvider {
+	if timeout <= 0 {
+		timeout = defaultRatesTimeout
+	}
+	return &HTTPExchangeRateProvider{url, format, &http.Client{Timeout: timeout}}
+}
+
+func (hp HTTPExchangeRateProvider) GetRates() (*domain.ExchangeRates, error) {
+	resp, err := hp.client.Get(hp.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Exchange rates request to %s failed with status %s", hp.url, resp.Status)
+	}
+
+	days, err := ParseExchangeRates(resp.Body, hp.format)
+	if err != nil {
+		return nil, err
+	}
+	return days[0], nil
+}
@@ -0,0 +1,64 @@
+package providers
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPExchangeRateProvider_GetRates(t *testing.T) {
+	testTable := []struct {
+		name          string
+		status        int
+		body          string
+		expectedDate  time.Time
+		expectedRates map[string]float64
+		expectedError bool
+	}{
+		{
+			name:          "rates of the newest day are returned",
+			status:        http.StatusOK,
+			body:          testECBXML,
+			expectedDate:  time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+			expectedRates: map[string]float64{"EUR": 1.0921, "PLN": 0.250166},
+		},
+		{
+			name:          "get error when the response is not 200",
+			status:        http.StatusServiceUnavailable,
+			body:          testECBXML,
+			expectedError: true,
+		},
+		{
+			name:          "get error when the body is malformed",
+			status:        http.StatusOK,
+			body:          "<html>maintenance</html>",
+			expectedError: true,
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(testCase.status)
+				w.Write([]byte(testCase.body))
+			}))
+			defer server.Close()
+			provider := NewHTTPExchangeRateProvider(server.URL+"/eurofxref-daily.xml", request.RatesFormatXML, time.Second)
+
+			wantResult, err := provider.GetRates()
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedDate, wantResult.Date)
+			assert.Len(t, wantResult.Rates, len(testCase.expectedRates))
+			for currency, rate := range testCase.expectedRates {
+				assert.InDelta(t, rate, wantResult.Rates[currency], 0.000001, currency)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package providers
+
+import (
+	"github.com/inkoba/app_for_HR/internal/config"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"strings"
+	"time"
+)
+
+// StaticExchangeRateProvider returns the coefficients and rates of the config
+type StaticExchangeRateProvider struct {
+	rates *domain.ExchangeRates
+}
+
+var _ ports.IExchangeRateProvider = (*StaticExchangeRateProvider)(nil)
+
+func NewStaticExchangeRateProvider(c config.CurrencyConfig) *StaticExchangeRateProvider {
+	rates := map[string]float64{
+		"EUR": c.CoefficientEURtoUSD,
+		"RUB": c.CoefficientRUStoUSD,
+	}
+	// the config keys are lower case
+	for currency, rate := range c.Rates {
+		rates[strings.ToUpper(currency)] = rate
+	}
+	return &StaticExchangeRateProvider{&domain.ExchangeRates{Date: time.Now(), Rates: rates}}
+}
+
+func (sp StaticExchangeRateProvider) GetRates() (*domain.ExchangeRates, error) {
+	return sp.rates, nil
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/ports"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// ExchangeRateRepository stores one document of rates per day with the day as its id
+type ExchangeRateRepository struct {
+	mc     *MongoConfig
+	logger *logrus.Logger
+}
+
+var _ ports.IExchangeRateRepository = (*ExchangeRateRepository)(nil)
+
+func NewExchangeRateRepository(mc *MongoConfig, logger *logrus.Logger) ports.IExchangeRateRepository {
+	return &ExchangeRateRepository{
+		mc,
+		logger,
+	}
+}
+
+// SaveRates replaces the stored rates of every day of days
+func (er ExchangeRateRepository) SaveRates(days []*domain.ExchangeRates) error {
+	models := make([]mongo.WriteModel, 0, len(days))
+	for _, day := range days {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": day.Date}).
+			SetReplacement(day).
+			SetUpsert(true))
+	}
+	_, err := er.mc.ratesCollection.BulkWrite(context.Background(), models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// GetRates returns the rates of the latest day on or before the date
+func (er ExchangeRateRepository) GetRates(date time.Time) (*domain.ExchangeRates, error) {
+	var rates domain.ExchangeRates
+	err := er.mc.ratesCollection.FindOne(context.Background(), bson.M{"_id": bson.M{"$lte": date}},
+		options.FindOne().SetSort(bson.M{"_id": -1})).Decode(&rates)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrExchangeRatesNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rates, nil
+}
+
+func (er ExchangeRateRepository) GetCurrencyRates() ([]*domain.CurrencyRate, error) {
+	cursor, err := er.mc.currencyRatesCollection.Find(context.Background(), bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var rates []*domain.CurrencyRate
+	if err := cursor.All(context.Background(), &rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (er ExchangeRateRepository) GetCurrencyRate(currency string) (*domain.CurrencyRate, error) {
+	var rate domain.CurrencyRate
+	err := er.mc.currencyRatesCollection.FindOne(context.Background(), bson.M{"_id": currency}).Decode(&rate)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrCurrencyRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (er ExchangeRateRepository) CreateCurrencyRate(rate *domain.CurrencyRate) error {
+	_, err := er.mc.currencyRatesCollection.InsertOne(context.Background(), rate)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrCurrencyRateExists
+	}
+	return err
+}
+
+// UpdateCurrencyRate replaces the rate of the currency and returns the previous one
+func (er ExchangeRateRepository) UpdateCurrencyRate(rate *domain.CurrencyRate) (*domain.CurrencyRate, error) {
+	var previous domain.CurrencyRate
+	err := er.mc.currencyRatesCollection.FindOneAndReplace(context.Background(), bson.M{"_id": rate.Currency}, rate).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrCurrencyRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &previous, nil
+}
+
+// DeleteCurrencyRate deletes the rate of the currency and returns it
+func (er ExchangeRateRepository) DeleteCurrencyRate(currency string) (*domain.CurrencyRate, error) {
+	var deleted domain.CurrencyRate
+	err := er.mc.currencyRatesCollection.FindOneAndDelete(context.Background(), bson.M{"_id": currency}).Decode(&deleted)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrCurrencyRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &deleted, nil
+}
+
+// AddCurrencyRateChange stores the change and sets its id
+func (er ExchangeRateRepository) AddCurrencyRateChange(change *domain.CurrencyRateChange) error {
+	result, err := er.mc.rateChangesCollection.InsertOne(context.Background(), change)
+	if err != nil {
+		return err
+	}
+	change.Id = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (er ExchangeRateRepository) DeleteCurrencyRateChange(id primitive.ObjectID) error {
+	_, err := er.mc.rateChangesCollection.DeleteOne(context.Background(), bson.M{"_id": id})
+	return err
+}
+
+// GetCurrencyRateChanges returns the changes of the currency, or of every currency when it is empty, newest first
+func (er ExchangeRateRepository) GetCurrencyRateChanges(currency string) ([]*domain.CurrencyRateChange, error) {
+	filter := bson.M{}
+	if len(currency) > 0 {
+		filter["currency"] = currency
+	}
+	cursor, err := er.mc.rateChangesCollection.Find(context.Background(), filter,
+		options.Find().SetSort(bson.D{{Key: "changedAt", Value: -1}, {Key: "_id", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*domain.CurrencyRateChange
+	if err := cursor.All(context.Background(), &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -4,15 +4,20 @@ import (
 	"context"
 	"github.com/inkoba/app_for_HR/internal/config"
 	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoConfig struct {
-	client             *mongo.Client
-	collection         *mongo.Collection
-	salariesCollection *mongo.Collection
-	logger             *logrus.Logger
+	client                  *mongo.Client
+	collection              *mongo.Collection
+	salariesCollection      *mongo.Collection
+	datasetsCollection      *mongo.Collection
+	ratesCollection         *mongo.Collection
+	currencyRatesCollection *mongo.Collection
+	rateChangesCollection   *mongo.Collection
+	logger                  *logrus.Logger
 }
 
 func NewMongoConfig(c config.Config, logger *logrus.Logger) *MongoConfig {
@@ -31,8 +36,20 @@ func NewMongoConfig(c config.Config, logger *logrus.Logger) *MongoConfig {
 
 	collection := client.Database(c.Database).Collection("users")
 	salariesCollection := client.Database(c.Database).Collection("salaries")
+	datasetsCollection := client.Database(c.Database).Collection("salary_datasets")
+	ratesCollection := client.Database(c.Database).Collection("exchange_rates")
+	currencyRatesCollection := client.Database(c.Database).Collection("currency_rates")
+	rateChangesCollection := client.Database(c.Database).Collection("currency_rate_changes")
 
-	return &MongoConfig{client, collection, salariesCollection, logger}
+	_, err = salariesCollection.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.M{"datasetid": 1}},
+		{Keys: bson.D{{Key: "datasetid", Value: 1}, {Key: "responseid", Value: 1}}},
+	})
+	if err != nil {
+		logger.Error(err)
+	}
+
+	return &MongoConfig{client, collection, salariesCollection, datasetsCollection, ratesCollection, currencyRatesCollection, rateChangesCollection, logger}
 }
 
 func (c MongoConfig) Ping() error {
@@ -7,11 +7,18 @@ import (
 	"github.com/inkoba/app_for_HR/internal/core/ports"
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"log"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// caseInsensitive compares strings ignoring the case in salary queries
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
 type SalaryRepository struct {
 	mc     *MongoConfig
 	logger *logrus.Logger
@@ -40,15 +47,227 @@ func (sr SalaryRepository) Create(salaries []*domain.Salary) error {
 	return err
 }
 
-func (sr SalaryRepository) DeleteAll() error {
-	return sr.mc.salariesCollection.Drop(context.Background())
+// Upsert replaces the salaries with the same response id in the dataset and inserts the new ones
+func (sr SalaryRepository) Upsert(salaries []*domain.Salary) error {
+	models := make([]mongo.WriteModel, 0, len(salaries))
+	for _, salary := range salaries {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"datasetid": salary.DatasetId, "responseid": salary.ResponseId}).
+			SetReplacement(salary).
+			SetUpsert(true))
+	}
+
+	_, err := sr.mc.salariesCollection.BulkWrite(context.Background(), models, options.BulkWrite().SetOrdered(true))
+	return err
+}
+
+// CreateDataset creates the staging dataset of the upload, a survey without a collection date is collected on the upload day
+func (sr SalaryRepository) CreateDataset(upload *request.SalaryUpload) (*domain.SalaryDataset, error) {
+	dataset := domain.SalaryDataset{
+		Status:    domain.DatasetStatusStaging,
+		Mode:      upload.Mode,
+		FileName:  upload.FileName,
+		Uploader:  upload.Uploader,
+		CreatedAt: time.Now(),
+	}
+	collectedAt := upload.CollectedAt
+	if collectedAt.IsZero() {
+		collectedAt = dataset.CreatedAt
+	}
+	dataset.CollectedAt = &collectedAt
+	if ratesDate := upload.RatesDate; !ratesDate.IsZero() {
+		dataset.RatesDate = &ratesDate
+	}
+	dataset.Rates = upload.Rates
+
+	result, err := sr.mc.datasetsCollection.InsertOne(context.Background(), dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset.Id = result.InsertedID.(primitive.ObjectID)
+	return &dataset, nil
+}
+
+// CopyActiveDataset copies the salaries of the live dataset into the dataset on the database side
+func (sr SalaryRepository) CopyActiveDataset(id primitive.ObjectID) error {
+	filter, err := sr.datasetFilter("")
+	if err != nil {
+		return err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$unset", Value: "_id"}},
+		{{Key: "$set", Value: bson.M{"datasetid": id}}},
+		{{Key: "$merge", Value: bson.M{"into": sr.mc.salariesCollection.Name()}}},
+	}
+	cursor, err := sr.mc.salariesCollection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.Close(context.Background())
+}
+
+func (sr SalaryRepository) GetDataset(id primitive.ObjectID) (*domain.SalaryDataset, error) {
+	var dataset domain.SalaryDataset
+	err := sr.mc.datasetsCollection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&dataset)
+	if err == mongo.ErrNoDocuments {
+		return nil, domain.ErrDatasetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := sr.GetActiveDataset()
+	if err == nil {
+		dataset.Active = active.Id == dataset.Id
+	} else if err != domain.ErrDatasetNotFound {
+		return nil, err
+	}
+	return &dataset, nil
+}
+
+func (sr SalaryRepository) GetActiveDataset() (*domain.SalaryDataset, error) {
+	datasets, err := sr.activatedDatasets(1)
+	if err != nil {
+		return nil, err
+	}
+	if len(datasets) == 0 {
+		return nil, domain.ErrDatasetNotFound
+	}
+
+	datasets[0].Active = true
+	return datasets[0], nil
 }
 
-func (sr SalaryRepository) GetFilteredSalaries(salaryFilteringCondition *request.ConditionForFilteringSalaries) ([]*domain.Salary, error) {
-	filter := filteredFields(salaryFilteringCondition)
-	cursor, err := sr.mc.salariesCollection.Find(context.Background(), filter)
+func (sr SalaryRepository) GetAllDatasets() ([]*domain.SalaryDataset, error) {
+	cursor, err := sr.mc.datasetsCollection.Find(context.Background(), bson.M{},
+		options.Find().SetSort(bson.M{"createdAt": -1}))
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+
+	var datasets []*domain.SalaryDataset
+	if err := cursor.All(context.Background(), &datasets); err != nil {
+		return nil, err
+	}
+
+	active, err := sr.GetActiveDataset()
+	if err == domain.ErrDatasetNotFound {
+		return datasets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, dataset := range datasets {
+		dataset.Active = dataset.Id == active.Id
+	}
+	return datasets, nil
+}
+
+// ActivateDataset makes the dataset live with a single document update, so readers never see a partial import
+func (sr SalaryRepository) ActivateDataset(id primitive.ObjectID) error {
+	update := bson.M{"$set": bson.M{"status": domain.DatasetStatusReady, "activatedAt": time.Now()}}
+	result, err := sr.mc.datasetsCollection.UpdateOne(context.Background(), bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrDatasetNotFound
+	}
+	return nil
+}
+
+func (sr SalaryRepository) DeleteDataset(id primitive.ObjectID) error {
+	_, err := sr.mc.salariesCollection.DeleteMany(context.Background(), bson.M{"datasetid": id})
+	if err != nil {
+		return err
+	}
+
+	result, err := sr.mc.datasetsCollection.DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrDatasetNotFound
+	}
+	return nil
+}
+
+// RollbackDataset activates again the dataset that was live before the current one
+func (sr SalaryRepository) RollbackDataset() (*domain.SalaryDataset, error) {
+	datasets, err := sr.activatedDatasets(2)
+	if err != nil {
+		return nil, err
+	}
+	if len(datasets) < 2 {
+		return nil, domain.ErrDatasetNotFound
+	}
+
+	previous := datasets[1]
+	activatedAt := time.Now()
+	_, err = sr.mc.datasetsCollection.UpdateOne(context.Background(), bson.M{"_id": previous.Id},
+		bson.M{"$set": bson.M{"activatedAt": activatedAt}})
+	if err != nil {
+		return nil, err
+	}
+
+	previous.ActivatedAt = &activatedAt
+	previous.Active = true
+	return previous, nil
+}
+
+// CountFilteredSalaries counts the salaries matching the condition in every page
+func (sr SalaryRepository) CountFilteredSalaries(salaryFilteringCondition *request.ConditionForFilteringSalaries) (int64, error) {
+	pipeline, err := sr.salariesPipeline(salaryFilteringCondition)
+	if err != nil {
+		return 0, err
+	}
+
+	var counts []struct {
+		Count int64 `bson:"count"`
+	}
+	err = sr.aggregate(append(pipeline, bson.D{{Key: "$count", Value: "count"}}), &counts)
+	if err != nil || len(counts) == 0 {
+		return 0, err
+	}
+	return counts[0].Count, nil
+}
+
+// GetFilteredSalaries passes the salaries of the page to each while they are read from the cursor,
+// the ids break ties of the sort so pages do not overlap. A page without a limit has every matching salary.
+func (sr SalaryRepository) GetFilteredSalaries(page *request.SalaryPageRequest, each func(salary *domain.Salary) error) error {
+	pipeline, err := sr.salariesPipeline(&page.ConditionForFilteringSalaries)
+	if err != nil {
+		return err
+	}
+
+	sort := bson.D{}
+	if len(page.SortBy) > 0 {
+		field, ok := sortByFields[page.SortBy]
+		if !ok {
+			return domain.NewValidationError("Salaries can not be sorted by %s", page.SortBy)
+		}
+		order := 1
+		if page.SortOrder == request.SortOrderDesc {
+			order = -1
+		}
+		sort = append(sort, bson.E{Key: field, Value: order})
+	}
+	sort = append(sort, bson.E{Key: "_id", Value: 1})
+
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sort}})
+	if page.Offset > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: page.Offset}})
+	}
+	if page.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: page.Limit}})
+	}
+	cursor, err := sr.mc.salariesCollection.Aggregate(context.Background(), pipeline,
+		options.Aggregate().SetCollation(caseInsensitive).SetAllowDiskUse(true))
+	if err != nil {
+		return err
 	}
 	defer func(cursor *mongo.Cursor, ctx context.Context) {
 		err := cursor.Close(ctx)
@@ -57,47 +276,452 @@ func (sr SalaryRepository) GetFilteredSalaries(salaryFilteringCondition *request
 		}
 	}(cursor, context.Background())
 
-	var list []*domain.Salary
 	for cursor.Next(context.Background()) {
 		var salary *domain.Salary
 
 		err := cursor.Decode(&salary)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err := each(salary); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+var sortByFields = map[string]string{
+	request.SortBySalary:     "amountusd",
+	request.SortByYearsTotal: "yearstotal",
+	request.SortByCountry:    "country",
+}
+
+// GetSalaryStats computes the statistics of the USD amounts in one aggregation
+func (sr SalaryRepository) GetSalaryStats(salaryFilteringCondition *request.ConditionForFilteringSalaries) (*domain.SalaryStats, error) {
+	pipeline, err := sr.salariesPipeline(salaryFilteringCondition)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*domain.SalaryGroupStats
+	err = sr.aggregate(statsPipeline(convertedSalaries(pipeline), nil), &groups)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return &domain.SalaryStats{}, nil
+	}
+	return &groups[0].SalaryStats, nil
+}
+
+// GetSalaryGroupStats computes the statistics of every group of salaries with the same values of the dimensions
+func (sr SalaryRepository) GetSalaryGroupStats(salaryFilteringCondition *request.ConditionForFilteringSalaries, groupBy []string) ([]*domain.SalaryGroupStats, error) {
+	pipeline, err := sr.salariesPipeline(salaryFilteringCondition)
+	if err != nil {
+		return nil, err
+	}
+
+	groupId := bson.M{}
+	sort := bson.D{}
+	for _, dimension := range groupBy {
+		expression, ok := groupByExpressions[dimension]
+		if !ok {
+			return nil, domain.NewValidationError("Salaries can not be grouped by %s", dimension)
+		}
+		groupId[dimension] = expression
+		sort = append(sort, bson.E{Key: "_id." + dimension, Value: 1})
+	}
+
+	pipeline = append(statsPipeline(convertedSalaries(pipeline), groupId), bson.D{{Key: "$sort", Value: sort}})
+	var groups []*domain.SalaryGroupStats
+	err = sr.aggregate(pipeline, &groups)
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// GetSalaryHistogram counts the salaries in every bucket of the layout, the outliers are counted
+// in the buckets -1 and layout.Buckets
+func (sr SalaryRepository) GetSalaryHistogram(salaryFilteringCondition *request.ConditionForFilteringSalaries, layout *domain.HistogramLayout) (*domain.SalaryHistogram, error) {
+	pipeline, err := sr.salariesPipeline(salaryFilteringCondition)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline = convertedSalaries(pipeline)
+	index := bson.M{"$toInt": bson.M{"$floor": bson.M{"$divide": bson.A{
+		bson.M{"$subtract": bson.A{"$amountusd", layout.Start}}, layout.Width,
+	}}}}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$switch": bson.M{
+				"branches": bson.A{
+					bson.M{"case": bson.M{"$lt": bson.A{"$amountusd", layout.Lower}}, "then": -1},
+					bson.M{"case": bson.M{"$gt": bson.A{"$amountusd", layout.Upper}}, "then": layout.Buckets},
+				},
+				"default": bson.M{"$max": bson.A{0, bson.M{"$min": bson.A{layout.Buckets - 1, index}}}},
+			}},
+			"count": bson.M{"$sum": 1},
+		}}},
+	)
+
+	var buckets []struct {
+		Index int `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	err = sr.aggregate(pipeline, &buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	histogram := &domain.SalaryHistogram{Counts: make([]int, layout.Buckets)}
+	for _, bucket := range buckets {
+		switch {
+		case bucket.Index < 0:
+			histogram.Below = bucket.Count
+		case bucket.Index >= layout.Buckets:
+			histogram.Above = bucket.Count
+		default:
+			histogram.Counts[bucket.Index] = bucket.Count
 		}
+	}
+	return histogram, nil
+}
+
+var groupByExpressions = map[string]interface{}{
+	domain.GroupByLevelOfSeniority: "$levelofseniority",
+	domain.GroupByCountry:          "$country",
+	domain.GroupByYearsTotal:       bson.M{"$floor": "$yearstotal"},
+	domain.GroupByLevelOfEnglish:   "$levelofenglish",
+}
+
+// statsPercentiles are the ranks computed by statsPipeline, in the order of the fields they are projected to
+var statsPercentiles = bson.A{0.1, 0.25, 0.5, 0.75, 0.9}
+
+// statsPipeline groups the salaries selected by the pipeline by groupId and computes the statistics of their USD amounts.
+// The percentiles are computed by $percentile (MongoDB 7.0+) without holding the amounts of a group in one document,
+// its approximate method returns one of the amounts so the median of two amounts is the lower one.
+func statsPipeline(pipeline mongo.Pipeline, groupId interface{}) mongo.Pipeline {
+	return append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":   groupId,
+			"count": bson.M{"$sum": 1},
+			"min":   bson.M{"$min": "$amountusd"},
+			"max":   bson.M{"$max": "$amountusd"},
+			"mean":  bson.M{"$avg": "$amountusd"},
+			"percentiles": bson.M{"$percentile": bson.M{
+				"input":  "$amountusd",
+				"p":      statsPercentiles,
+				"method": "approximate",
+			}},
+		}}},
+		bson.D{{Key: "$project", Value: bson.M{
+			"count":  1,
+			"min":    1,
+			"max":    1,
+			"mean":   1,
+			"p10":    bson.M{"$arrayElemAt": bson.A{"$percentiles", 0}},
+			"p25":    bson.M{"$arrayElemAt": bson.A{"$percentiles", 1}},
+			"median": bson.M{"$arrayElemAt": bson.A{"$percentiles", 2}},
+			"p75":    bson.M{"$arrayElemAt": bson.A{"$percentiles", 3}},
+			"p90":    bson.M{"$arrayElemAt": bson.A{"$percentiles", 4}},
+		}}},
+	)
+}
+
+// aggregate runs the pipeline on the salaries, stages over the memory limit of the server spill to disk
+func (sr SalaryRepository) aggregate(pipeline mongo.Pipeline, result interface{}) error {
+	cursor, err := sr.mc.salariesCollection.Aggregate(context.Background(), pipeline,
+		options.Aggregate().SetCollation(caseInsensitive).SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	// All closes the cursor
+	return cursor.All(context.Background(), result)
+}
+
+// salariesFilter selects the salaries of the requested or live dataset that match the condition
+func (sr SalaryRepository) salariesFilter(salaryFilteringCondition *request.ConditionForFilteringSalaries) (bson.M, error) {
+	filter, err := sr.datasetFilter(salaryFilteringCondition.Version)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := filteredFields(salaryFilteringCondition)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		filter[key] = value
+	}
+	return filter, nil
+}
+
+// salariesPipeline selects the salaries matching the condition. With today's rates the USD amounts
+// are converted again from the original amounts before the salary bounds are applied.
+func (sr SalaryRepository) salariesPipeline(salaryFilteringCondition *request.ConditionForFilteringSalaries) (mongo.Pipeline, error) {
+	filter, err := sr.salariesFilter(salaryFilteringCondition)
+	if err != nil {
+		return nil, err
+	}
+	if salaryFilteringCondition.USDRates == nil {
+		return mongo.Pipeline{{{Key: "$match", Value: filter}}}, nil
+	}
+
+	amountUSD, ok := filter["amountusd"]
+	delete(filter, "amountusd")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$set", Value: bson.M{"amountusd": currentAmountUSD(salaryFilteringCondition.USDRates)}}},
+	}
+	if ok {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"amountusd": amountUSD}}})
+	}
+	return pipeline, nil
+}
 
-		result := &domain.Salary{
-			Salary:           salary.Salary,
-			LevelOfSeniority: salary.LevelOfSeniority,
-			YearsTotal:       salary.YearsTotal,
-			Country:          salary.Country,
-			Currency:         salary.Currency,
+// convertedSalaries keeps the salaries of the pipeline that have a USD amount, the statistics are computed from them
+func convertedSalaries(pipeline mongo.Pipeline) mongo.Pipeline {
+	return append(pipeline, bson.D{{Key: "$match", Value: bson.M{"amountusd": bson.M{"$ne": nil}}}})
+}
+
+// currentAmountUSD converts the original amount with the rates, salaries in other currencies get no USD amount
+func currentAmountUSD(usdRates map[string]float64) bson.M {
+	currencies := make([]string, 0, len(usdRates))
+	for currency := range usdRates {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	branches := bson.A{}
+	for _, currency := range currencies {
+		branches = append(branches, bson.M{"case": bson.M{"$eq": bson.A{"$currency", currency}}, "then": usdRates[currency]})
+	}
+	rate := bson.M{"$switch": bson.M{"branches": branches, "default": nil}}
+	return bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{"$amount", rate}}, 2}}
+}
+
+// datasetFilter selects the salaries of the requested version or of the live dataset when no version is given.
+// Salaries imported before datasets existed have no dataset and stay visible until the first dataset is activated.
+func (sr SalaryRepository) datasetFilter(version string) (bson.M, error) {
+	if len(strings.TrimSpace(version)) > 0 {
+		id, err := primitive.ObjectIDFromHex(strings.TrimSpace(version))
+		if err != nil {
+			return nil, domain.ErrDatasetNotFound
 		}
+		dataset, err := sr.GetDataset(id)
+		if err != nil {
+			return nil, err
+		}
+		if dataset.Status != domain.DatasetStatusReady {
+			return nil, domain.ErrDatasetNotFound
+		}
+		return bson.M{"datasetid": dataset.Id}, nil
+	}
 
-		list = append(list, result)
+	active, err := sr.GetActiveDataset()
+	if err == domain.ErrDatasetNotFound {
+		return bson.M{"datasetid": bson.M{"$exists": false}}, nil
 	}
-	if err := cursor.Err(); err != nil {
+	if err != nil {
 		return nil, err
 	}
+	return bson.M{"datasetid": active.Id}, nil
+}
 
-	return list, nil
+func (sr SalaryRepository) activatedDatasets(limit int64) ([]*domain.SalaryDataset, error) {
+	findOptions := options.Find().SetSort(bson.M{"activatedAt": -1})
+	if limit > 0 {
+		findOptions.SetLimit(limit)
+	}
+
+	cursor, err := sr.mc.datasetsCollection.Find(context.Background(),
+		bson.M{"status": domain.DatasetStatusReady, "activatedAt": bson.M{"$exists": true}}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var datasets []*domain.SalaryDataset
+	if err := cursor.All(context.Background(), &datasets); err != nil {
+		return nil, err
+	}
+	return datasets, nil
 }
 
-func filteredFields(filterSalary *request.ConditionForFilteringSalaries) bson.M {
+// filteredFields selects the salaries matching the fields of the condition. Salaries without a USD amount
+// are kept so they can be reported as not converted, unless a salary range is requested.
+func filteredFields(filterSalary *request.ConditionForFilteringSalaries) (bson.M, error) {
 	filter := bson.M{}
+	if filterSalary.SalaryMin != nil || filterSalary.SalaryMax != nil {
+		amountUSD := bson.M{"$ne": nil}
+		addRange(amountUSD, filterSalary.SalaryMin, filterSalary.SalaryMax)
+		filter["amountusd"] = amountUSD
+	}
 
 	if len(strings.TrimSpace(filterSalary.Salary)) > 0 {
-		filter["salary"] = filterSalary.Salary
-	}
-	if len(strings.TrimSpace(filterSalary.Country)) > 0 {
-		filter["country"] = filterSalary.Country
+		amount, err := strconv.ParseFloat(strings.TrimSpace(filterSalary.Salary), 64)
+		if err != nil {
+			return nil, domain.NewValidationError("Salary filter %q is not a number", filterSalary.Salary)
+		}
+		filter["amount"] = amount
 	}
+	addValuesFilter(filter, "country", filterSalary.Country, filterSalary.Countries)
+
+	yearsTotal := bson.M{}
 	if len(strings.TrimSpace(filterSalary.YearsTotal)) > 0 {
-		filter["yearstotal"] = filterSalary.YearsTotal
+		years, err := strconv.ParseFloat(strings.TrimSpace(filterSalary.YearsTotal), 64)
+		if err != nil {
+			return nil, domain.NewValidationError("Years total filter %q is not a number", filterSalary.YearsTotal)
+		}
+		yearsTotal["$eq"] = years
 	}
-	if len(strings.TrimSpace(filterSalary.LevelOfSeniority)) > 0 {
-		filter["levelofseniority"] = filterSalary.LevelOfSeniority
+	addRange(yearsTotal, filterSalary.YearsTotalMin, filterSalary.YearsTotalMax)
+	if len(yearsTotal) > 0 {
+		filter["yearstotal"] = yearsTotal
+	}
+
+	addValuesFilter(filter, "levelofseniority", filterSalary.LevelOfSeniority, filterSalary.LevelsOfSeniority)
+	seniorityRank := bson.M{}
+	addRange(seniorityRank, filterSalary.SeniorityRankMin, filterSalary.SeniorityRankMax)
+	if len(seniorityRank) > 0 {
+		filter["seniorityrank"] = seniorityRank
 	}
+	addValuesFilter(filter, "levelofenglish", filterSalary.LevelOfEnglish, filterSalary.LevelsOfEnglish)
 
-	return filter
+	return filter, nil
+}
+
+// addValuesFilter adds the exact value and the in and not in lists of a categorical field,
+// the case is ignored through the caseInsensitive collation of the query
+func addValuesFilter(filter bson.M, key string, value string, values *request.ValuesFilter) {
+	field := bson.M{}
+	if len(strings.TrimSpace(value)) > 0 {
+		field["$eq"] = strings.TrimSpace(value)
+	}
+	if values != nil {
+		if in := nonEmptyValues(values.In); len(in) > 0 {
+			field["$in"] = in
+		}
+		if notIn := nonEmptyValues(values.NotIn); len(notIn) > 0 {
+			field["$nin"] = notIn
+		}
+	}
+	if len(field) > 0 {
+		filter[key] = field
+	}
+}
+
+func nonEmptyValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); len(value) > 0 {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// addRange adds the inclusive bounds that are set to the field filter
+func addRange(field bson.M, min *float64, max *float64) {
+	if min != nil {
+		field["$gte"] = *min
+	}
+	if max != nil {
+		field["$lte"] = *max
+	}
+}
+
+// MigrateLegacySalaries converts salaries stored with string amount and years to the numeric fields
+// and converts the salaries imported before datasets existed that have no USD amount with the rates.
+// The original values are kept in rawsalary and rawyearstotal, values that are not numbers become null,
+// salaries in a currency without a rate keep no USD amount and are reported when they are read.
+func (sr SalaryRepository) MigrateLegacySalaries(usdRates map[string]float64) error {
+	toDouble := func(field string) bson.M {
+		return bson.M{"$convert": bson.M{"input": field, "to": "double", "onError": nil, "onNull": nil}}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"rawsalary":     bson.M{"$trim": bson.M{"input": bson.M{"$concat": bson.A{"$salary", " ", bson.M{"$ifNull": bson.A{"$currency", ""}}}}}},
+			"rawyearstotal": "$yearstotal",
+			"amount":        toDouble("$salary"),
+			"amountmin":     toDouble("$salary"),
+			"amountmax":     toDouble("$salary"),
+			"amountusd":     nil,
+			"yearstotal":    toDouble("$yearstotal"),
+			"currency": bson.M{"$switch": bson.M{
+				"branches": bson.A{bson.M{"case": bson.M{"$in": bson.A{"$currency", bson.A{"RUS", "RUR"}}}, "then": "RUB"}},
+				"default":  "$currency",
+			}},
+		}}},
+		{{Key: "$unset", Value: "salary"}},
+	}
+
+	result, err := sr.mc.salariesCollection.UpdateMany(context.Background(),
+		bson.M{"amount": bson.M{"$exists": false}, "salary": bson.M{"$type": "string"}}, pipeline)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount > 0 {
+		sr.logger.Info("Legacy salaries migrated to numeric fields: ", result.ModifiedCount)
+	}
+
+	currencies := make([]string, 0, len(usdRates))
+	for currency := range usdRates {
+		currencies = append(currencies, currency)
+	}
+	result, err = sr.mc.salariesCollection.UpdateMany(context.Background(),
+		bson.M{"datasetid": bson.M{"$exists": false}, "amountusd": nil, "currency": bson.M{"$in": currencies}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"amountusd": currentAmountUSD(usdRates)}}}})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount > 0 {
+		sr.logger.Info("Legacy salaries converted to USD: ", result.ModifiedCount)
+	}
+	return nil
+}
+
+// MigrateLegacySeniority maps the levels of seniority of the salaries stored before the seniority taxonomy existed
+// to the levels of the taxonomy like an import does, the original values are kept in rawlevelofseniority
+func (sr SalaryRepository) MigrateLegacySeniority(taxonomy *domain.SeniorityTaxonomy) error {
+	values, err := sr.mc.salariesCollection.Distinct(context.Background(), "levelofseniority",
+		bson.M{"seniorityrank": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+
+	models := legacySeniorityUpdates(values, taxonomy)
+	if len(models) == 0 {
+		return nil
+	}
+	result, err := sr.mc.salariesCollection.BulkWrite(context.Background(), models)
+	if err != nil {
+		return err
+	}
+	sr.logger.Info("Legacy salaries mapped to the seniority taxonomy: ", result.ModifiedCount)
+	return nil
+}
+
+// legacySeniorityUpdates sets the level and rank of the taxonomy for every stored level of seniority,
+// levels that are not in the taxonomy are trimmed and get rank 0
+func legacySeniorityUpdates(values []interface{}, taxonomy *domain.SeniorityTaxonomy) []mongo.WriteModel {
+	var models []mongo.WriteModel
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		levelOfSeniority, seniorityRank := strings.TrimSpace(raw), 0
+		if level, ok := taxonomy.Level(raw); ok {
+			levelOfSeniority, seniorityRank = level.Name, level.Rank
+		}
+		models = append(models, mongo.NewUpdateManyModel().
+			SetFilter(bson.M{"levelofseniority": raw, "seniorityrank": bson.M{"$exists": false}}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"levelofseniority":    levelOfSeniority,
+				"seniorityrank":       seniorityRank,
+				"rawlevelofseniority": raw,
+			}}))
+	}
+	return models
 }
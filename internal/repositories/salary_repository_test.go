@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"github.com/inkoba/app_for_HR/internal/core/domain"
+	"github.com/inkoba/app_for_HR/internal/core/domain/request"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"testing"
+)
+
+func TestFilteredFields(t *testing.T) {
+	testTable := []struct {
+		name          string
+		condition     *request.ConditionForFilteringSalaries
+		expected      bson.M
+		expectedError error
+	}{
+		{
+			name:      "empty condition selects every salary",
+			condition: &request.ConditionForFilteringSalaries{},
+			expected:  bson.M{},
+		},
+		{
+			name: "single values are compared with $eq",
+			condition: &request.ConditionForFilteringSalaries{
+				Salary:           " 1500 ",
+				Country:          " Poland ",
+				YearsTotal:       "3",
+				LevelOfSeniority: "Senior",
+				LevelOfEnglish:   "B2",
+			},
+			expected: bson.M{
+				"amount":           1500.0,
+				"country":          bson.M{"$eq": "Poland"},
+				"yearstotal":       bson.M{"$eq": 3.0},
+				"levelofseniority": bson.M{"$eq": "Senior"},
+				"levelofenglish":   bson.M{"$eq": "B2"},
+			},
+		},
+		{
+			name: "multiple values and exclusions use $in and $nin",
+			condition: &request.ConditionForFilteringSalaries{
+				Countries:         &request.ValuesFilter{In: []string{"Poland", "Ukraine"}, NotIn: []string{"Germany"}},
+				LevelsOfSeniority: &request.ValuesFilter{NotIn: []string{"Junior", "Middle"}},
+				LevelsOfEnglish:   &request.ValuesFilter{In: []string{"C1"}},
+			},
+			expected: bson.M{
+				"country":          bson.M{"$in": []string{"Poland", "Ukraine"}, "$nin": []string{"Germany"}},
+				"levelofseniority": bson.M{"$nin": []string{"Junior", "Middle"}},
+				"levelofenglish":   bson.M{"$in": []string{"C1"}},
+			},
+		},
+		{
+			name: "single value is combined with the lists",
+			condition: &request.ConditionForFilteringSalaries{
+				Country:   "Poland",
+				Countries: &request.ValuesFilter{NotIn: []string{"Germany"}},
+			},
+			expected: bson.M{"country": bson.M{"$eq": "Poland", "$nin": []string{"Germany"}}},
+		},
+		{
+			name: "empty values are ignored",
+			condition: &request.ConditionForFilteringSalaries{
+				Salary:            " ",
+				Country:           "  ",
+				YearsTotal:        "",
+				Countries:         &request.ValuesFilter{In: []string{"", " "}, NotIn: []string{}},
+				LevelsOfSeniority: &request.ValuesFilter{In: []string{" ", "Lead "}},
+			},
+			expected: bson.M{"levelofseniority": bson.M{"$in": []string{"Lead"}}},
+		},
+		{
+			name: "salary range keeps only salaries with a USD amount",
+			condition: &request.ConditionForFilteringSalaries{
+				SalaryMin: floatPointer(1000),
+				SalaryMax: floatPointer(3000),
+			},
+			expected: bson.M{"amountusd": bson.M{"$ne": nil, "$gte": 1000.0, "$lte": 3000.0}},
+		},
+		{
+			name:      "salary range with one bound",
+			condition: &request.ConditionForFilteringSalaries{SalaryMax: floatPointer(3000)},
+			expected:  bson.M{"amountusd": bson.M{"$ne": nil, "$lte": 3000.0}},
+		},
+		{
+			name: "years total range is combined with the exact value",
+			condition: &request.ConditionForFilteringSalaries{
+				YearsTotal:    "4",
+				YearsTotalMin: floatPointer(2),
+			},
+			expected: bson.M{"yearstotal": bson.M{"$eq": 4.0, "$gte": 2.0}},
+		},
+		{
+			name: "seniority rank range",
+			condition: &request.ConditionForFilteringSalaries{
+				SeniorityMin:     "Middle",
+				SeniorityMax:     "Lead",
+				SeniorityRankMin: floatPointer(2),
+				SeniorityRankMax: floatPointer(4),
+			},
+			expected: bson.M{"seniorityrank": bson.M{"$gte": 2.0, "$lte": 4.0}},
+		},
+		{
+			name:          "get error when the salary is not a number",
+			condition:     &request.ConditionForFilteringSalaries{Salary: "much"},
+			expectedError: domain.NewValidationError("Salary filter %q is not a number", "much"),
+		},
+		{
+			name:          "get error when the years total is not a number",
+			condition:     &request.ConditionForFilteringSalaries{YearsTotal: "many"},
+			expectedError: domain.NewValidationError("Years total filter %q is not a number", "many"),
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			wantResult, err := filteredFields(testCase.condition)
+
+			if testCase.expectedError != nil {
+				assert.Equal(t, testCase.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, wantResult)
+		})
+	}
+}
+
+func TestAddRange(t *testing.T) {
+	testTable := []struct {
+		name     string
+		field    bson.M
+		min      *float64
+		max      *float64
+		expected bson.M
+	}{
+		{
+			name:     "no bounds leave the field unchanged",
+			field:    bson.M{},
+			expected: bson.M{},
+		},
+		{
+			name:     "minimum only",
+			field:    bson.M{},
+			min:      floatPointer(1),
+			expected: bson.M{"$gte": 1.0},
+		},
+		{
+			name:     "maximum only",
+			field:    bson.M{},
+			max:      floatPointer(5),
+			expected: bson.M{"$lte": 5.0},
+		},
+		{
+			name:     "zero bounds are kept",
+			field:    bson.M{},
+			min:      floatPointer(0),
+			max:      floatPointer(0),
+			expected: bson.M{"$gte": 0.0, "$lte": 0.0},
+		},
+		{
+			name:     "bounds are added to the existing operators",
+			field:    bson.M{"$ne": nil},
+			min:      floatPointer(1),
+			max:      floatPointer(5),
+			expected: bson.M{"$ne": nil, "$gte": 1.0, "$lte": 5.0},
+		},
+	}
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			addRange(testCase.field, testCase.min, testCase.max)
+
+			assert.Equal(t, testCase.expected, testCase.field)
+		})
+	}
+}
+
+func TestStatsPipeline(t *testing.T) {
+	match := bson.D{{Key: "$match", Value: bson.M{"country": bson.M{"$eq": "Poland"}}}}
+
+	pipeline := statsPipeline(mongo.Pipeline{match}, "$country")
+
+	assert.Len(t, pipeline, 3)
+	assert.Equal(t, match, pipeline[0])
+	group := pipeline[1][0].Value.(bson.M)
+	assert.Equal(t, "$country", group["_id"])
+	assert.Equal(t, bson.M{"$percentile": bson.M{"input": "$amountusd", "p": statsPercentiles, "method": "approximate"}},
+		group["percentiles"])
+	for _, accumulator := range group {
+		if operators, ok := accumulator.(bson.M); ok {
+			assert.NotContains(t, operators, "$push", "amounts of a group are not held in one document")
+		}
+	}
+	project := pipeline[2][0].Value.(bson.M)
+	assert.Equal(t, bson.M{"$arrayElemAt": bson.A{"$percentiles", 2}}, project["median"])
+}
+
+func TestLegacySeniorityUpdates(t *testing.T) {
+	taxonomy, err := domain.NewSeniorityTaxonomy([]*domain.SeniorityLevel{
+		{Name: "Junior", Aliases: []string{"Jr"}},
+		{Name: "Middle", Aliases: []string{"Mid"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	models := legacySeniorityUpdates([]interface{}{"jr.", "Middle", " Architect ", nil}, taxonomy)
+
+	update := func(raw string, level string, rank int) mongo.WriteModel {
+		return mongo.NewUpdateManyModel().
+			SetFilter(bson.M{"levelofseniority": raw, "seniorityrank": bson.M{"$exists": false}}).
+			SetUpdate(bson.M{"$set": bson.M{"levelofseniority": level, "seniorityrank": rank, "rawlevelofseniority": raw}})
+	}
+	assert.Equal(t, []mongo.WriteModel{
+		update("jr.", "Junior", 1),
+		update("Middle", "Middle", 2),
+		update(" Architect ", "Architect", 0),
+	}, models)
+}
+
+func floatPointer(value float64) *float64 {
+	return &value
+}